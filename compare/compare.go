@@ -0,0 +1,258 @@
+package compare
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/reporter"
+)
+
+// Defaults for Options
+const (
+	DefaultTolerance      = 0.05
+	DefaultErrorTolerance = 0.01
+	DefaultAlpha          = 0.05
+)
+
+// TotalScope names the scope covering all requests
+const TotalScope = "all requests"
+
+// Options sets how large and how certain a change must be to count
+type Options struct {
+	// Tolerance is the relative latency increase or throughput decrease
+	// accepted without a regression, e.g. 0.05 for 5%.
+	Tolerance float64
+	// ErrorTolerance is the accepted absolute error rate increase, e.g. 0.01 for one percentage point.
+	ErrorTolerance float64
+	// Alpha is the significance level a change must reach when it can be tested.
+	Alpha float64
+}
+
+// DefaultOptions returns the default tolerances and significance level
+func DefaultOptions() Options {
+	return Options{Tolerance: DefaultTolerance, ErrorTolerance: DefaultErrorTolerance, Alpha: DefaultAlpha}
+}
+
+// Delta compares one metric between the runs
+type Delta struct {
+	Metric     string  // "achieved_rps", "error_rate" or a percentile label such as "p99"
+	Baseline   float64 // req/s, error rate 0..1, or ms
+	Current    float64
+	Change     float64 // Relative change; for error_rate the difference in rate
+	PValue     float64 // One-sided p-value of the current run being worse (when Tested)
+	Tested     bool    // Whether samples or counts allowed a significance test
+	Regression bool
+	Improved   bool // Better by more than the tolerance
+}
+
+// Scope holds the deltas of all requests or of one endpoint
+type Scope struct {
+	Name   string
+	Deltas []Delta
+}
+
+// Result is the comparison of a run against a baseline
+type Result struct {
+	Baseline, Current reporter.ReportMetadata
+	Options           Options
+	Scopes            []Scope
+	Added             []string // Endpoints only in the current run
+	Removed           []string // Endpoints only in the baseline
+}
+
+// Regressions counts the deltas flagged as regressions
+func (r *Result) Regressions() int {
+	n := 0
+	for _, sc := range r.Scopes {
+		for _, d := range sc.Deltas {
+			if d.Regression {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Compare reports how current differs from baseline, overall and for each
+// endpoint present in both. A change is a regression when it exceeds the
+// tolerance and, where a test is possible, is significant at opts.Alpha:
+// latency uses the Mann-Whitney U test on the reports' samples (recorded
+// with metrics.sampleSize), error rate a two-proportion z-test. Throughput
+// and unsampled latency are judged by the tolerance alone.
+func Compare(baseline, current *reporter.Report, opts Options) *Result {
+	res := &Result{Baseline: baseline.Metadata, Current: current.Metadata, Options: opts}
+	var labels []string
+	for _, p := range baseline.Config.Percentiles {
+		labels = append(labels, metrics.PercentileLabel(p))
+	}
+
+	res.Scopes = append(res.Scopes, compareScope(TotalScope, baseline.Results, current.Results,
+		sampleDurations(baseline.Samples, ""), sampleDurations(current.Samples, ""), labels, opts))
+
+	for _, name := range sortedNames(baseline.Endpoints) {
+		cur, ok := current.Endpoints[name]
+		if !ok {
+			res.Removed = append(res.Removed, name)
+			continue
+		}
+		res.Scopes = append(res.Scopes, compareScope(name, baseline.Endpoints[name], cur,
+			sampleDurations(baseline.Samples, name), sampleDurations(current.Samples, name), labels, opts))
+	}
+	for _, name := range sortedNames(current.Endpoints) {
+		if _, ok := baseline.Endpoints[name]; !ok {
+			res.Added = append(res.Added, name)
+		}
+	}
+	return res
+}
+
+// compareScope compares throughput, error rate and each percentile of one scope
+func compareScope(name string, base, cur reporter.ResultSummary, baseSamples, curSamples []float64, labels []string, opts Options) Scope {
+	sc := Scope{Name: name}
+
+	rps := Delta{Metric: "achieved_rps", Baseline: base.AchievedRPS, Current: cur.AchievedRPS}
+	rps.Change = relativeChange(rps.Baseline, rps.Current)
+	rps.Regression = rps.Change < -opts.Tolerance
+	rps.Improved = rps.Change > opts.Tolerance
+	sc.Deltas = append(sc.Deltas, rps)
+
+	errs := Delta{Metric: "error_rate", Baseline: base.ErrorRate, Current: cur.ErrorRate}
+	errs.Change = cur.ErrorRate - base.ErrorRate
+	errs.PValue, errs.Tested = twoProportionGreater(base.Failed, base.Requests, cur.Failed, cur.Requests)
+	errs.Regression = errs.Change > opts.ErrorTolerance && (!errs.Tested || errs.PValue < opts.Alpha)
+	errs.Improved = errs.Change < -opts.ErrorTolerance
+	sc.Deltas = append(sc.Deltas, errs)
+
+	// One test per scope: it asks whether the whole distribution shifted up
+	p, tested := mannWhitneyGreater(baseSamples, curSamples)
+	for _, label := range labels {
+		b, okBase := base.Latency.Percentiles[label]
+		c, okCur := cur.Latency.Percentiles[label]
+		if !okBase || !okCur {
+			continue
+		}
+		d := Delta{Metric: label, Baseline: b, Current: c, PValue: p, Tested: tested}
+		d.Change = relativeChange(b, c)
+		d.Regression = d.Change > opts.Tolerance && (!tested || p < opts.Alpha)
+		d.Improved = d.Change < -opts.Tolerance
+		sc.Deltas = append(sc.Deltas, d)
+	}
+	return sc
+}
+
+// Write prints the comparison as a table followed by the regression count
+func Write(w io.Writer, res *Result) {
+	fmt.Fprintf(w, "Baseline: %s\n", describeRun(res.Baseline))
+	fmt.Fprintf(w, "Current:  %s\n", describeRun(res.Current))
+	fmt.Fprintf(w, "Tolerance: %.1f%% latency/throughput, %.2fpp error rate, alpha %.2f\n\n",
+		res.Options.Tolerance*100, res.Options.ErrorTolerance*100, res.Options.Alpha)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Scope\tMetric\tBaseline\tCurrent\tChange\tp-value\tVerdict")
+	fmt.Fprintln(tw, "-----\t------\t--------\t-------\t------\t-------\t-------")
+	for _, sc := range res.Scopes {
+		for _, d := range sc.Deltas {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				sc.Name, d.Metric, FormatValue(d.Metric, d.Baseline), FormatValue(d.Metric, d.Current),
+				FormatChange(d), FormatPValue(d), Verdict(d))
+		}
+	}
+	_ = tw.Flush()
+
+	if len(res.Added) > 0 {
+		fmt.Fprintf(w, "\nOnly in the current run: %s\n", strings.Join(res.Added, ", "))
+	}
+	if len(res.Removed) > 0 {
+		fmt.Fprintf(w, "\nOnly in the baseline: %s\n", strings.Join(res.Removed, ", "))
+	}
+	fmt.Fprintf(w, "\nRegressions: %d\n", res.Regressions())
+}
+
+// FormatValue renders a metric value with its unit
+func FormatValue(metric string, v float64) string {
+	switch metric {
+	case "achieved_rps":
+		return fmt.Sprintf("%.1f req/s", v)
+	case "error_rate":
+		return fmt.Sprintf("%.2f%%", v*100)
+	default:
+		return fmt.Sprintf("%.2fms", v)
+	}
+}
+
+// FormatChange renders the change: relative in percent, error rate in percentage points
+func FormatChange(d Delta) string {
+	if d.Metric == "error_rate" {
+		return fmt.Sprintf("%+.2fpp", d.Change*100)
+	}
+	if math.IsInf(d.Change, 1) {
+		return "new"
+	}
+	return fmt.Sprintf("%+.1f%%", d.Change*100)
+}
+
+// FormatPValue renders the p-value, or "-" when no test was run
+func FormatPValue(d Delta) string {
+	if !d.Tested {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f", d.PValue)
+}
+
+// Verdict is "REGRESSION", "improved" or "ok"
+func Verdict(d Delta) string {
+	switch {
+	case d.Regression:
+		return "REGRESSION"
+	case d.Improved:
+		return "improved"
+	default:
+		return "ok"
+	}
+}
+
+// describeRun identifies a report by start time and config file
+func describeRun(m reporter.ReportMetadata) string {
+	s := m.StartedAt.Format("2006-01-02 15:04:05 MST")
+	if m.ConfigFile != "" {
+		s += " (" + m.ConfigFile + ")"
+	}
+	return s
+}
+
+// relativeChange is (current-baseline)/baseline; growth from zero is +Inf
+func relativeChange(baseline, current float64) float64 {
+	if baseline == 0 {
+		if current == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (current - baseline) / baseline
+}
+
+// sampleDurations returns the sampled service times of one endpoint, or of
+// all requests when endpoint is empty
+func sampleDurations(samples []reporter.SampleSummary, endpoint string) []float64 {
+	var out []float64
+	for _, s := range samples {
+		if endpoint == "" || s.Endpoint == endpoint {
+			out = append(out, s.DurationMs)
+		}
+	}
+	return out
+}
+
+func sortedNames(m map[string]reporter.ResultSummary) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,137 @@
+package compare
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"benchmarking-tool/reporter"
+)
+
+// testReport builds a report with two endpoints; get_user latencies are
+// 10..59ms scaled by slowdown, and its samples match.
+func testReport(slowdown float64, failed int64, rps float64) *reporter.Report {
+	rep := &reporter.Report{
+		Metadata: reporter.ReportMetadata{StartedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), ConfigFile: "bench.yml"},
+		Config:   reporter.ConfigSummary{Percentiles: []float64{50, 99}},
+		Endpoints: map[string]reporter.ResultSummary{
+			"get_user": {
+				Requests: 1000, Failed: failed, ErrorRate: float64(failed) / 1000, AchievedRPS: rps,
+				Latency: reporter.LatencySummary{Percentiles: map[string]float64{"p50": 35 * slowdown, "p99": 59 * slowdown}},
+			},
+			"health": {
+				Requests: 1000, AchievedRPS: rps,
+				Latency: reporter.LatencySummary{Percentiles: map[string]float64{"p50": 1, "p99": 2}},
+			},
+		},
+	}
+	rep.Results = rep.Endpoints["get_user"]
+	for _, v := range series(10, 1, 50) {
+		rep.Samples = append(rep.Samples, reporter.SampleSummary{Endpoint: "get_user", DurationMs: v * slowdown})
+	}
+	return rep
+}
+
+func findDelta(t *testing.T, res *Result, scope, metric string) Delta {
+	t.Helper()
+	for _, sc := range res.Scopes {
+		if sc.Name != scope {
+			continue
+		}
+		for _, d := range sc.Deltas {
+			if d.Metric == metric {
+				return d
+			}
+		}
+	}
+	t.Fatalf("no %s delta for %s", metric, scope)
+	return Delta{}
+}
+
+func TestCompare_Regressions(t *testing.T) {
+	res := Compare(testReport(1, 1, 100), testReport(2, 60, 80), DefaultOptions())
+
+	if len(res.Scopes) != 3 || res.Scopes[0].Name != TotalScope || res.Scopes[1].Name != "get_user" {
+		t.Fatalf("unexpected scopes %+v", res.Scopes)
+	}
+	p99 := findDelta(t, res, "get_user", "p99")
+	if !p99.Regression || !p99.Tested || p99.PValue > 0.001 || p99.Change != 1 {
+		t.Errorf("doubled latency should be a significant regression, got %+v", p99)
+	}
+	if d := findDelta(t, res, "get_user", "error_rate"); !d.Regression || !d.Tested {
+		t.Errorf("0.1%% to 6%% errors should be a regression, got %+v", d)
+	}
+	if d := findDelta(t, res, "get_user", "achieved_rps"); !d.Regression || d.Change != -0.2 {
+		t.Errorf("a 20%% throughput drop should be a regression, got %+v", d)
+	}
+	if d := findDelta(t, res, "health", "p99"); d.Regression || d.Tested {
+		t.Errorf("unchanged health latency without samples should pass untested, got %+v", d)
+	}
+	if res.Regressions() < 3 {
+		t.Errorf("expected at least 3 regressions, got %d", res.Regressions())
+	}
+}
+
+func TestCompare_WithinTolerance(t *testing.T) {
+	res := Compare(testReport(1, 10, 100), testReport(1.03, 11, 98), DefaultOptions())
+	if n := res.Regressions(); n != 0 {
+		t.Fatalf("changes within tolerance should not regress, got %d", n)
+	}
+	res = Compare(testReport(1, 10, 100), testReport(0.5, 0, 130), DefaultOptions())
+	if d := findDelta(t, res, "get_user", "p50"); !d.Improved || Verdict(d) != "improved" {
+		t.Errorf("halved latency should be an improvement, got %+v", d)
+	}
+}
+
+func TestCompare_AddedAndRemovedEndpoints(t *testing.T) {
+	base, cur := testReport(1, 0, 100), testReport(1, 0, 100)
+	delete(cur.Endpoints, "health")
+	cur.Endpoints["create_user"] = reporter.ResultSummary{Requests: 10}
+
+	res := Compare(base, cur, DefaultOptions())
+	if len(res.Added) != 1 || res.Added[0] != "create_user" || len(res.Removed) != 1 || res.Removed[0] != "health" {
+		t.Fatalf("unexpected added %v / removed %v", res.Added, res.Removed)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	res := Compare(testReport(1, 1, 100), testReport(2, 1, 100), DefaultOptions())
+	var buf bytes.Buffer
+	Write(&buf, res)
+	out := buf.String()
+	for _, s := range []string{"Baseline: 2025-01-02 03:04:05 UTC (bench.yml)", "Tolerance: 5.0%", "get_user", "p99", "59.00ms", "118.00ms", "+100.0%", "REGRESSION", "Regressions: 4"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output should contain %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	base, cur := testReport(1, 1, 100), testReport(2, 1, 100)
+	cur.Endpoints["create|user"] = reporter.ResultSummary{Requests: 10}
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, Compare(base, cur, DefaultOptions())); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"### Comparison with baseline", "**❌ 4 regression(s)**", "Tolerance 5.0%",
+		"| Scope | Metric | Baseline | Current | Change | p-value | Verdict |",
+		"| get_user | p99 | 59.00ms | 118.00ms | +100.0% | 0.000 | ❌ REGRESSION |",
+		"| health | p99 | 2.00ms | 2.00ms | +0.0% | - | ok |",
+		"Only in the current run: create|user",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Markdown should contain %q:\n%s", s, out)
+		}
+	}
+
+	buf.Reset()
+	if err := WriteMarkdown(&buf, Compare(base, base, DefaultOptions())); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "**✅ No regressions**") {
+		t.Errorf("an unchanged run should have no regressions:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+package compare
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"benchmarking-tool/reporter"
+)
+
+// WriteMarkdown writes the comparison as a Markdown section, to follow
+// reporter.WriteMarkdown in a pull request comment or CI job summary.
+func WriteMarkdown(w io.Writer, res *Result) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "### Comparison with baseline")
+	fmt.Fprintln(bw)
+	if n := res.Regressions(); n > 0 {
+		fmt.Fprintf(bw, "**❌ %d regression(s)**\n\n", n)
+	} else {
+		fmt.Fprintln(bw, "**✅ No regressions**")
+		fmt.Fprintln(bw)
+	}
+	fmt.Fprintf(bw, "Baseline %s, current %s. Tolerance %.1f%% latency/throughput, %.2fpp error rate, alpha %.2f.\n\n",
+		describeRun(res.Baseline), describeRun(res.Current), res.Options.Tolerance*100, res.Options.ErrorTolerance*100, res.Options.Alpha)
+
+	var rows [][]string
+	for _, sc := range res.Scopes {
+		for _, d := range sc.Deltas {
+			rows = append(rows, []string{
+				sc.Name, d.Metric, FormatValue(d.Metric, d.Baseline), FormatValue(d.Metric, d.Current),
+				FormatChange(d), FormatPValue(d), markdownVerdict(d),
+			})
+		}
+	}
+	reporter.WriteMarkdownTable(bw, []string{"Scope", "Metric", "Baseline", "Current", "Change", "p-value", "Verdict"}, rows)
+
+	if len(res.Added) > 0 {
+		fmt.Fprintf(bw, "\nOnly in the current run: %s\n", strings.Join(res.Added, ", "))
+	}
+	if len(res.Removed) > 0 {
+		fmt.Fprintf(bw, "\nOnly in the baseline: %s\n", strings.Join(res.Removed, ", "))
+	}
+	return bw.Flush()
+}
+
+// markdownVerdict marks regressions and improvements so they stand out in a rendered table
+func markdownVerdict(d Delta) string {
+	switch {
+	case d.Regression:
+		return "❌ " + Verdict(d)
+	case d.Improved:
+		return "✅ " + Verdict(d)
+	default:
+		return Verdict(d)
+	}
+}
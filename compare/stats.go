@@ -0,0 +1,81 @@
+package compare
+
+import (
+	"math"
+	"sort"
+)
+
+// minSamples is the fewest samples per side for the Mann-Whitney test; below
+// it the normal approximation is too rough to trust.
+const minSamples = 8
+
+// mannWhitneyGreater is the one-sided Mann-Whitney U test that current tends
+// to be larger than baseline, using the normal approximation with tie and
+// continuity corrections. ok is false when either side has too few samples.
+func mannWhitneyGreater(baseline, current []float64) (p float64, ok bool) {
+	n1, n2 := len(baseline), len(current)
+	if n1 < minSamples || n2 < minSamples {
+		return 0, false
+	}
+	type obs struct {
+		v       float64
+		current bool
+	}
+	all := make([]obs, 0, n1+n2)
+	for _, v := range baseline {
+		all = append(all, obs{v, false})
+	}
+	for _, v := range current {
+		all = append(all, obs{v, true})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// Tied values share the mean of their ranks
+	var rankSum, tieTerm float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2 // Ranks i+1..j
+		for k := i; k < j; k++ {
+			if all[k].current {
+				rankSum += rank
+			}
+		}
+		t := float64(j - i)
+		tieTerm += t*t*t - t
+		i = j
+	}
+
+	n := float64(n1 + n2)
+	u := rankSum - float64(n2)*float64(n2+1)/2
+	mean := float64(n1) * float64(n2) / 2
+	variance := float64(n1) * float64(n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1, true // Every value identical
+	}
+	z := (u - mean - 0.5) / math.Sqrt(variance)
+	return upperTail(z), true
+}
+
+// twoProportionGreater is the one-sided two-proportion z-test that the
+// current failure rate is higher than the baseline's.
+func twoProportionGreater(baseFailed, baseTotal, curFailed, curTotal int64) (p float64, ok bool) {
+	if baseTotal == 0 || curTotal == 0 {
+		return 0, false
+	}
+	p1 := float64(baseFailed) / float64(baseTotal)
+	p2 := float64(curFailed) / float64(curTotal)
+	pooled := float64(baseFailed+curFailed) / float64(baseTotal+curTotal)
+	se := math.Sqrt(pooled * (1 - pooled) * (1/float64(baseTotal) + 1/float64(curTotal)))
+	if se == 0 {
+		return 1, true // Both runs all failed or all succeeded
+	}
+	return upperTail((p2 - p1) / se), true
+}
+
+// upperTail is P(Z > z) for a standard normal Z
+func upperTail(z float64) float64 {
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
@@ -0,0 +1,46 @@
+package compare
+
+import "testing"
+
+func series(start, step float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = start + float64(i)*step
+	}
+	return out
+}
+
+func TestMannWhitneyGreater(t *testing.T) {
+	base := series(10, 1, 50) // 10..59
+
+	if p, ok := mannWhitneyGreater(base, series(40, 1, 50)); !ok || p > 0.001 {
+		t.Errorf("shifted-up current should be significant, got p=%v ok=%v", p, ok)
+	}
+	if p, ok := mannWhitneyGreater(base, series(10, 1, 50)); !ok || p < 0.4 {
+		t.Errorf("identical samples should not be significant, got p=%v", p)
+	}
+	if p, _ := mannWhitneyGreater(base, series(0, 0.5, 50)); p < 0.99 {
+		t.Errorf("a faster current run is not a regression, got p=%v", p)
+	}
+	if p, ok := mannWhitneyGreater(series(5, 0, 20), series(5, 0, 20)); !ok || p != 1 {
+		t.Errorf("all-tied samples should give p=1, got p=%v ok=%v", p, ok)
+	}
+	if _, ok := mannWhitneyGreater(base, series(40, 1, minSamples-1)); ok {
+		t.Error("too few samples should not be tested")
+	}
+}
+
+func TestTwoProportionGreater(t *testing.T) {
+	if p, ok := twoProportionGreater(1, 1000, 50, 1000); !ok || p > 0.001 {
+		t.Errorf("1 vs 5%% failures should be significant, got p=%v", p)
+	}
+	if p, _ := twoProportionGreater(10, 1000, 12, 1000); p < 0.05 {
+		t.Errorf("1.0%% vs 1.2%% failures should not be significant, got p=%v", p)
+	}
+	if p, ok := twoProportionGreater(0, 100, 0, 100); !ok || p != 1 {
+		t.Errorf("no failures on either side should give p=1, got %v", p)
+	}
+	if _, ok := twoProportionGreater(0, 0, 1, 10); ok {
+		t.Error("an empty run should not be tested")
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Arrival process types
+const (
+	ArrivalUniform = "uniform"
+	ArrivalPoisson = "poisson"
+	ArrivalBursty  = "bursty"
+	ArrivalReplay  = "replay"
+)
+
+// maxReplayGap bounds a single replay gap; anything longer is almost
+// certainly a unit mistake and would overflow a time.Duration if huge
+const maxReplayGap = time.Hour
+
+// ArrivalConfig selects the inter-arrival process that rate-scheduled modes
+// (fixed, ramp, search) use to feed the worker queue. Every process keeps the
+// configured long-run mean RPS.
+type ArrivalConfig struct {
+	Type  string `yaml:"type,omitempty"`  // "uniform" (default), "poisson", "bursty" or "replay"
+	OnMs  int    `yaml:"onMs,omitempty"`  // bursty: length of each burst
+	OffMs int    `yaml:"offMs,omitempty"` // bursty: silence between bursts
+	File  string `yaml:"file,omitempty"`  // replay: inter-arrival gaps in ms, one per line
+
+	replayGaps []time.Duration // Loaded from File by Validate
+}
+
+// UnmarshalYAML accepts either a bare type (`arrival: poisson`) or the full mapping.
+func (a *ArrivalConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	var typ string
+	if err := unmarshal(&typ); err == nil {
+		*a = ArrivalConfig{Type: typ}
+		return nil
+	}
+	type plain ArrivalConfig
+	return unmarshal((*plain)(a))
+}
+
+// ReplayGaps returns the inter-arrival gaps read from File (replay only).
+func (a ArrivalConfig) ReplayGaps() []time.Duration {
+	return a.replayGaps
+}
+
+// validate checks the arrival settings and loads the replay file; relative
+// paths are resolved against dir (the config file's directory).
+func (a *ArrivalConfig) validate(dir string) error {
+	switch a.Type {
+	case ArrivalUniform, ArrivalPoisson:
+	case ArrivalBursty:
+		if a.OnMs <= 0 || a.OffMs <= 0 {
+			return fmt.Errorf("execution.arrival: bursty needs positive onMs and offMs")
+		}
+	case ArrivalReplay:
+		if a.File == "" {
+			return fmt.Errorf("execution.arrival: replay needs a file")
+		}
+		path := a.File
+		if !filepath.IsAbs(path) && dir != "" {
+			path = filepath.Join(dir, path)
+		}
+		gaps, err := readGapFile(path)
+		if err != nil {
+			return fmt.Errorf("execution.arrival: %w", err)
+		}
+		a.replayGaps = gaps
+	default:
+		return fmt.Errorf("unknown execution.arrival.type %q", a.Type)
+	}
+	return nil
+}
+
+// readGapFile parses one millisecond gap per line; blank lines and # comments are skipped.
+func readGapFile(path string) ([]time.Duration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replay file '%s': %w", path, err)
+	}
+	defer f.Close()
+
+	var gaps []time.Duration
+	var total time.Duration
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		ms, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsNaN(ms) || ms < 0 || ms > float64(maxReplayGap/time.Millisecond) {
+			return nil, fmt.Errorf("replay file '%s' line %d: expected between 0 and %d milliseconds, got %q", path, line, maxReplayGap.Milliseconds(), text)
+		}
+		gap := time.Duration(ms * float64(time.Millisecond))
+		gaps = append(gaps, gap)
+		total += gap
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read replay file '%s': %w", path, err)
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("replay file '%s' has no positive gaps", path)
+	}
+	return gaps, nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig_ArrivalShorthand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cfg.yaml")
+	yaml := `
+baseUrls: ["http://127.0.0.1:9"]
+execution:
+  mode: fixed
+  requestsPerSecond: 10
+  arrival: Poisson
+endpoints:
+  ep:
+    path: "/"
+    method: "GET"
+`
+	if err := os.WriteFile(path, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Execution.Arrival.Type != ArrivalPoisson {
+		t.Fatalf("expected poisson arrival, got %q", cfg.Execution.Arrival.Type)
+	}
+}
+
+func TestLoadConfig_ArrivalReplayRelativeFile(t *testing.T) {
+	dir := t.TempDir()
+	gaps := "# captured from prod\n10\n\n20.5\n0\n"
+	if err := os.WriteFile(filepath.Join(dir, "gaps.txt"), []byte(gaps), 0600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "cfg.yaml")
+	yaml := `
+baseUrls: ["http://127.0.0.1:9"]
+execution:
+  mode: fixed
+  requestsPerSecond: 10
+  arrival:
+    type: replay
+    file: gaps.txt
+endpoints:
+  ep:
+    path: "/"
+    method: "GET"
+`
+	if err := os.WriteFile(path, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := cfg.Execution.Arrival.ReplayGaps()
+	want := []time.Duration{10 * time.Millisecond, 20500 * time.Microsecond, 0}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d gaps, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("gap %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestValidate_ArrivalInvalid(t *testing.T) {
+	dir := t.TempDir()
+	badGaps := filepath.Join(dir, "bad.txt")
+	if err := os.WriteFile(badGaps, []byte("5\nfast\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	zeroGaps := filepath.Join(dir, "zero.txt")
+	if err := os.WriteFile(zeroGaps, []byte("0\n0\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	gapFile := func(name, line string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("5\n"+line+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	testCases := []struct {
+		name    string
+		arrival ArrivalConfig
+		want    string
+	}{
+		{"unknown type", ArrivalConfig{Type: "gaussian"}, "unknown execution.arrival.type"},
+		{"bursty without periods", ArrivalConfig{Type: "bursty", OnMs: 100}, "onMs and offMs"},
+		{"replay without file", ArrivalConfig{Type: "replay"}, "needs a file"},
+		{"replay missing file", ArrivalConfig{Type: "replay", File: filepath.Join(dir, "nope.txt")}, "failed to open"},
+		{"replay bad line", ArrivalConfig{Type: "replay", File: badGaps}, "line 2"},
+		{"replay all zero", ArrivalConfig{Type: "replay", File: zeroGaps}, "no positive gaps"},
+		{"replay NaN", ArrivalConfig{Type: "replay", File: gapFile("nan.txt", "NaN")}, "line 2"},
+		{"replay infinity", ArrivalConfig{Type: "replay", File: gapFile("inf.txt", "+Inf")}, "line 2"},
+		{"replay huge gap", ArrivalConfig{Type: "replay", File: gapFile("huge.txt", "1e300")}, "line 2"},
+		{"replay gap over an hour", ArrivalConfig{Type: "replay", File: gapFile("hour.txt", "3600001")}, "line 2"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := minimalValidConfig()
+			c.Execution.Arrival = tc.arrival
+			if err := c.Validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
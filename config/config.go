@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v2"
@@ -12,12 +14,70 @@ const (
 	maxWorkersCap    = 8192
 	maxQueueDepthCap = 1_000_000
 	maxRateBurstCap  = 10_000
+	maxSampleSizeCap = 1_000_000
+
+	// maxWindowSecondsCap bounds metrics.windowSeconds to one hour
+	maxWindowSecondsCap = 3600
+
+	// defaultMaxModeWorkers is the worker count for max mode when maxWorkers is omitted
+	defaultMaxModeWorkers = 64
+)
+
+// Ramp stage curve shapes
+const (
+	CurveLinear      = "linear"
+	CurveExponential = "exponential"
+	CurveStep        = "step"
 )
 
+// StageConfig defines one segment of a ramp or concurrency profile
+type StageConfig struct {
+	Name            string `yaml:"name,omitempty" json:"name,omitempty"`               // Label used in the report (default "stage-N")
+	StartRPS        int    `yaml:"startRps,omitempty" json:"startRps,omitempty"`       // Ramp mode: rate at the beginning of the stage
+	TargetRPS       int    `yaml:"targetRps,omitempty" json:"targetRps,omitempty"`     // Ramp mode: rate at the end of the stage
+	StartUsers      int    `yaml:"startUsers,omitempty" json:"startUsers,omitempty"`   // Concurrency mode: virtual users at the beginning of the stage
+	TargetUsers     int    `yaml:"targetUsers,omitempty" json:"targetUsers,omitempty"` // Concurrency mode: virtual users at the end of the stage
+	DurationSeconds int    `yaml:"durationSeconds" json:"durationSeconds"`             // Length of the stage
+	Curve           string `yaml:"curve,omitempty" json:"curve,omitempty"`             // "linear" (default), "exponential" or "step"
+}
+
+// Bounds returns the start and target load of the stage for the given mode
+// (RPS for ramp, virtual users for concurrency).
+func (s StageConfig) Bounds(mode string) (start, target int) {
+	if strings.EqualFold(mode, "concurrency") {
+		return s.StartUsers, s.TargetUsers
+	}
+	return s.StartRPS, s.TargetRPS
+}
+
+// Search mode strategies
+const (
+	SearchStep   = "step"
+	SearchBisect = "bisect"
+)
+
+// SLOConfig lists the objectives a search step must meet to pass
+type SLOConfig struct {
+	P99LatencyMs     int     `yaml:"p99LatencyMs,omitempty" json:"p99LatencyMs,omitempty"`         // Maximum p99 latency (0 = not checked)
+	MaxErrorRate     float64 `yaml:"maxErrorRate,omitempty" json:"maxErrorRate,omitempty"`         // Maximum failed fraction, 0..1 (0 = no errors allowed)
+	MinAchievedRatio float64 `yaml:"minAchievedRatio,omitempty" json:"minAchievedRatio,omitempty"` // Minimum achieved/target RPS (default 0.9)
+}
+
+// SearchConfig drives search mode, which looks for the highest RPS that meets the SLOs
+type SearchConfig struct {
+	Strategy            string    `yaml:"strategy,omitempty" json:"strategy,omitempty"`         // "step" (default) or "bisect"
+	StartRPS            int       `yaml:"startRps" json:"startRps"`                             // First rate tried
+	MaxRPS              int       `yaml:"maxRps" json:"maxRps"`                                 // Upper bound of the search
+	StepRPS             int       `yaml:"stepRps,omitempty" json:"stepRps,omitempty"`           // Step strategy increment (default a tenth of the range)
+	PrecisionRPS        int       `yaml:"precisionRps,omitempty" json:"precisionRps,omitempty"` // Bisect stops when the bracket is this narrow (default 1/32 of the range)
+	StepDurationSeconds int       `yaml:"stepDurationSeconds" json:"stepDurationSeconds"`       // How long each rate is held
+	SLO                 SLOConfig `yaml:"slo" json:"slo"`
+}
+
 // ExecutionConfig defines how the benchmark should run
 type ExecutionConfig struct {
-	Mode              string `yaml:"mode"`              // "fixed" or "ramp"
-	DurationSeconds   int    `yaml:"durationSeconds"`   // Total duration for the test
+	Mode              string `yaml:"mode"`              // "fixed", "ramp", "concurrency", "max" or "search"
+	DurationSeconds   int    `yaml:"durationSeconds"`   // Total duration for the test (staged modes: sum of stages)
 	RequestTimeoutMs  int    `yaml:"requestTimeoutMs"`  // Timeout for individual HTTP requests
 	RequestsPerSecond int    `yaml:"requestsPerSecond"` // RPS for fixed mode
 	// MaxWorkers caps concurrent HTTP request goroutines. 0 after load means auto: min(256, max(1, RPS)).
@@ -26,6 +86,165 @@ type ExecutionConfig struct {
 	MaxQueueDepth int `yaml:"maxQueueDepth,omitempty"`
 	// RateBurst is the token-bucket burst for golang.org/x/time/rate (default 1).
 	RateBurst int `yaml:"rateBurst,omitempty"`
+	// Arrival is the inter-arrival process of rate-scheduled modes (default uniform).
+	Arrival ArrivalConfig `yaml:"arrival,omitempty"`
+	// Stages is the load profile for ramp (RPS) or concurrency (virtual users) mode, run in order.
+	Stages []StageConfig `yaml:"stages,omitempty"`
+	// VirtualUsers is the constant number of closed-loop users in concurrency mode without stages.
+	VirtualUsers int `yaml:"virtualUsers,omitempty"`
+	// ThinkTimeMs is how long each virtual user pauses after a response before its next request.
+	ThinkTimeMs int `yaml:"thinkTimeMs,omitempty"`
+	// Search configures search mode.
+	Search SearchConfig `yaml:"search,omitempty"`
+}
+
+// MetricsConfig tunes how latencies are summarised
+type MetricsConfig struct {
+	// Percentiles reported for service and response time, 0 < p <= 100 (default 50, 90, 95, 99).
+	Percentiles []float64 `yaml:"percentiles,omitempty"`
+	// HistogramPrecision is the number of significant digits kept by latency histograms, 1-5 (default 3).
+	HistogramPrecision int `yaml:"histogramPrecision,omitempty"`
+	// SampleSize keeps a uniform random sample of this many raw requests for exports (default 0: none).
+	SampleSize int `yaml:"sampleSize,omitempty"`
+	// WindowSeconds is the width of the time-series windows results are bucketed into (default 1).
+	WindowSeconds int `yaml:"windowSeconds,omitempty"`
+	// Prometheus is the listen address (e.g. ":9464") of a /metrics endpoint served during the run.
+	Prometheus string `yaml:"prometheus,omitempty"`
+	// Influx pushes windowed results in InfluxDB line protocol during the run.
+	Influx InfluxConfig `yaml:"influx,omitempty"`
+}
+
+// InfluxConfig sends windowed aggregates in InfluxDB line protocol to an
+// HTTP write endpoint or a file; it is off when neither is set.
+type InfluxConfig struct {
+	URL             string            `yaml:"url,omitempty"`             // Write endpoint, e.g. http://influx:8086/api/v2/write?org=o&bucket=b
+	File            string            `yaml:"file,omitempty"`            // Line protocol file, instead of url
+	Token           string            `yaml:"token,omitempty"`           // Sent as "Authorization: Token ..."
+	Measurement     string            `yaml:"measurement,omitempty"`     // Default "benchmark"
+	Tags            map[string]string `yaml:"tags,omitempty"`            // Added to every point
+	IntervalSeconds int               `yaml:"intervalSeconds,omitempty"` // Time between batches (default 5)
+}
+
+// Enabled reports whether a destination is configured
+func (i InfluxConfig) Enabled() bool {
+	return i.URL != "" || i.File != ""
+}
+
+// validate checks the destination and batch interval
+func (i InfluxConfig) validate() error {
+	if i.URL != "" && i.File != "" {
+		return fmt.Errorf("metrics.influx: set either url or file, not both")
+	}
+	if i.URL != "" {
+		u, err := url.Parse(i.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("metrics.influx.url must be an http(s) URL, got %q", i.URL)
+		}
+	}
+	if i.IntervalSeconds < 0 || i.IntervalSeconds > maxWindowSecondsCap {
+		return fmt.Errorf("metrics.influx.intervalSeconds must be between 0 and %d", maxWindowSecondsCap)
+	}
+	for k, v := range i.Tags {
+		if k == "" || k == "scope" || k == "endpoint" {
+			return fmt.Errorf("metrics.influx.tags: tag key %q is empty or reserved", k)
+		}
+		if v == "" { // Line protocol has no empty tag values
+			return fmt.Errorf("metrics.influx.tags: tag %q has an empty value", k)
+		}
+	}
+	return nil
+}
+
+// TracingConfig controls W3C trace context propagation and the export of
+// client spans. Every request gets a new trace ID.
+type TracingConfig struct {
+	// DisablePropagation stops the traceparent header from being sent.
+	DisablePropagation bool `yaml:"disablePropagation,omitempty"`
+	// OTLPEndpoint is an OTLP/HTTP traces URL (e.g. http://collector:4318/v1/traces)
+	// client spans are exported to; empty disables the export.
+	OTLPEndpoint string            `yaml:"otlpEndpoint,omitempty"`
+	OTLPHeaders  map[string]string `yaml:"otlpHeaders,omitempty"` // Sent with every export, e.g. for authentication
+	ServiceName  string            `yaml:"serviceName,omitempty"` // service.name of the spans (default "benchmarking-tool")
+}
+
+// validate checks the OTLP endpoint
+func (t TracingConfig) validate() error {
+	if t.OTLPEndpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(t.OTLPEndpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("tracing.otlpEndpoint must be an http(s) URL, got %q", t.OTLPEndpoint)
+	}
+	return nil
+}
+
+// ReportConfig replaces the built-in console report with a user template
+type ReportConfig struct {
+	// Template is a Go template file rendered with the report model (the
+	// structure of the JSON report). Files ending in .html or .htm use
+	// html/template, others text/template. Relative paths are resolved
+	// against the config file's directory.
+	Template string `yaml:"template,omitempty"`
+	// Output is where the rendered template is written; when empty it
+	// replaces the console report on stdout.
+	Output string `yaml:"output,omitempty"`
+
+	templatePath string // Template resolved by Validate
+}
+
+// TemplatePath returns the template file resolved against the config directory
+func (r ReportConfig) TemplatePath() string {
+	return r.templatePath
+}
+
+// validate checks that the template file exists; relative paths are resolved against dir
+func (r *ReportConfig) validate(dir string) error {
+	if r.Template == "" {
+		if r.Output != "" {
+			return fmt.Errorf("report.output needs report.template")
+		}
+		return nil
+	}
+	path := r.Template
+	if !filepath.IsAbs(path) && dir != "" {
+		path = filepath.Join(dir, path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("report.template: %w", err)
+	}
+	r.templatePath = path
+	return nil
+}
+
+// OutputConfig lists the report files written after a run, in addition to the
+// console report. Empty paths are skipped.
+type OutputConfig struct {
+	JSON       string `yaml:"json,omitempty"`       // Machine-readable report
+	SamplesCSV string `yaml:"samplesCsv,omitempty"` // One row per request, streamed during the run
+	SummaryCSV string `yaml:"summaryCsv,omitempty"` // Aggregates for the run, stages, endpoints and base URLs
+	HTML       string `yaml:"html,omitempty"`       // Self-contained report with charts
+	JUnit      string `yaml:"junit,omitempty"`      // Thresholds as JUnit XML test cases, for CI
+	Markdown   string `yaml:"markdown,omitempty"`   // Summary tables for pull requests and CI job summaries
+}
+
+// validate checks the percentile list and histogram precision
+func (m MetricsConfig) validate() error {
+	for _, p := range m.Percentiles {
+		if p <= 0 || p > 100 {
+			return fmt.Errorf("metrics.percentiles: %v must be in (0, 100]", p)
+		}
+	}
+	if m.HistogramPrecision != 0 && (m.HistogramPrecision < 1 || m.HistogramPrecision > 5) {
+		return fmt.Errorf("metrics.histogramPrecision must be between 1 and 5")
+	}
+	if m.SampleSize < 0 || m.SampleSize > maxSampleSizeCap {
+		return fmt.Errorf("metrics.sampleSize must be between 0 and %d", maxSampleSizeCap)
+	}
+	if m.WindowSeconds < 0 || m.WindowSeconds > maxWindowSecondsCap {
+		return fmt.Errorf("metrics.windowSeconds must be between 0 and %d", maxWindowSecondsCap)
+	}
+	return m.Influx.validate()
 }
 
 // ParameterGenerator defines how to generate parameter values
@@ -78,11 +297,18 @@ type Config struct {
 	ParameterGenerators map[string]ParameterGenerator `yaml:"parameterGenerators"`
 	Endpoints           map[string]EndpointConfig     `yaml:"endpoints"`
 	EndpointSelection   EndpointSelectionConfig       `yaml:"endpointSelection"`
+	Metrics             MetricsConfig                 `yaml:"metrics,omitempty"`
+	Output              OutputConfig                  `yaml:"output,omitempty"`
+	Thresholds          ThresholdsConfig              `yaml:"thresholds,omitempty"`
+	Tracing             TracingConfig                 `yaml:"tracing,omitempty"`
+	Report              ReportConfig                  `yaml:"report,omitempty"`
 	engine              *ParameterEngine              // Internal engine for parameter generation
+	dir                 string                        // Directory of the loaded file, for relative paths
 }
 
-// LoadConfig loads configuration from a YAML file
-func LoadConfig(filePath string) (*Config, error) {
+// LoadConfig loads configuration from a YAML file. Overrides are applied in
+// order on top of the file, before defaults and validation.
+func LoadConfig(filePath string, overrides ...Override) (*Config, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -96,10 +322,19 @@ func LoadConfig(filePath string) (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config data from '%s': %w", filePath, err)
 	}
+	for _, o := range overrides {
+		if err := o.apply(&cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.checkDurationOverride(overrides); err != nil {
+		return nil, err
+	}
 
 	if cfg.EndpointSelection.Strategy == "" {
 		cfg.EndpointSelection.Strategy = "roundRobin"
 	}
+	cfg.dir = filepath.Dir(filePath)
 
 	// Initialize parameter engine
 	cfg.engine = NewParameterEngine()
@@ -124,17 +359,27 @@ func (c *Config) applyExecutionDefaults() {
 	if c.Execution.RequestTimeoutMs == 0 {
 		c.Execution.RequestTimeoutMs = 5000
 	}
+	c.Execution.Mode = strings.ToLower(c.Execution.Mode)
 	if c.Execution.Mode == "" {
 		c.Execution.Mode = "fixed"
 	}
+	if c.isStaged() {
+		c.applyStageDefaults()
+	}
+	if strings.EqualFold(c.Execution.Mode, "search") {
+		c.applySearchDefaults()
+	}
 	if c.Execution.DurationSeconds == 0 {
 		c.Execution.DurationSeconds = 60
 	}
 	if c.Execution.RequestsPerSecond == 0 {
 		c.Execution.RequestsPerSecond = 10
 	}
+	if c.Execution.MaxWorkers == 0 && strings.EqualFold(c.Execution.Mode, "max") {
+		c.Execution.MaxWorkers = defaultMaxModeWorkers
+	}
 	if c.Execution.MaxWorkers == 0 {
-		rps := c.Execution.RequestsPerSecond
+		rps := c.peakLoad()
 		if rps < 1 {
 			rps = 1
 		}
@@ -150,6 +395,184 @@ func (c *Config) applyExecutionDefaults() {
 	if c.Execution.RateBurst == 0 {
 		c.Execution.RateBurst = 1
 	}
+	if c.Execution.Arrival.Type == "" {
+		c.Execution.Arrival.Type = ArrivalUniform
+	}
+	c.Execution.Arrival.Type = strings.ToLower(c.Execution.Arrival.Type)
+}
+
+// applyStageDefaults names unnamed stages, defaults curves to linear and
+// derives the total duration from the stage durations.
+func (c *Config) applyStageDefaults() {
+	total := 0
+	for i := range c.Execution.Stages {
+		st := &c.Execution.Stages[i]
+		if st.Name == "" {
+			st.Name = fmt.Sprintf("stage-%d", i+1)
+		}
+		if st.Curve == "" {
+			st.Curve = CurveLinear
+		}
+		st.Curve = strings.ToLower(st.Curve)
+		total += st.DurationSeconds
+	}
+	if total > 0 {
+		c.Execution.DurationSeconds = total
+	}
+}
+
+// applySearchDefaults fills in the step size, bisect precision and SLO ratio,
+// and sets the total duration to the longest the search can take.
+func (c *Config) applySearchDefaults() {
+	sc := &c.Execution.Search
+	if sc.Strategy == "" {
+		sc.Strategy = SearchStep
+	}
+	sc.Strategy = strings.ToLower(sc.Strategy)
+	span := sc.MaxRPS - sc.StartRPS
+	if sc.StepRPS == 0 {
+		sc.StepRPS = max(1, span/10)
+	}
+	if sc.PrecisionRPS == 0 {
+		sc.PrecisionRPS = max(1, span/32)
+	}
+	if sc.SLO.MinAchievedRatio == 0 {
+		sc.SLO.MinAchievedRatio = 0.9
+	}
+	if steps := c.maxSearchSteps(); steps > 0 && sc.StepDurationSeconds > 0 {
+		c.Execution.DurationSeconds = steps * sc.StepDurationSeconds
+	}
+}
+
+// maxSearchSteps is the most steps the configured search can run.
+func (c *Config) maxSearchSteps() int {
+	sc := c.Execution.Search
+	span := sc.MaxRPS - sc.StartRPS
+	if span <= 0 || sc.StepRPS <= 0 || sc.PrecisionRPS <= 0 {
+		return 0
+	}
+	if sc.Strategy == SearchBisect {
+		steps := 2 // start and max are always probed first
+		for width := span; width > sc.PrecisionRPS; width = (width + 1) / 2 {
+			steps++
+		}
+		return steps
+	}
+	return (span+sc.StepRPS-1)/sc.StepRPS + 1 // The last step is clamped to maxRps
+}
+
+// validateSearch checks the search mode settings.
+func (c *Config) validateSearch() error {
+	sc := c.Execution.Search
+	switch sc.Strategy {
+	case SearchStep, SearchBisect:
+	default:
+		return fmt.Errorf("unknown execution.search.strategy %q", sc.Strategy)
+	}
+	if sc.StartRPS <= 0 {
+		return fmt.Errorf("execution.search.startRps must be positive")
+	}
+	if sc.MaxRPS <= sc.StartRPS {
+		return fmt.Errorf("execution.search.maxRps must be greater than startRps")
+	}
+	if sc.StepRPS < 0 || sc.PrecisionRPS < 0 {
+		return fmt.Errorf("execution.search.stepRps and precisionRps must not be negative")
+	}
+	if sc.StepDurationSeconds <= 0 {
+		return fmt.Errorf("execution.search.stepDurationSeconds must be positive")
+	}
+	if sc.SLO.P99LatencyMs < 0 {
+		return fmt.Errorf("execution.search.slo.p99LatencyMs must not be negative")
+	}
+	if sc.SLO.MaxErrorRate < 0 || sc.SLO.MaxErrorRate > 1 {
+		return fmt.Errorf("execution.search.slo.maxErrorRate must be between 0 and 1")
+	}
+	if sc.SLO.MinAchievedRatio < 0 || sc.SLO.MinAchievedRatio > 1 {
+		return fmt.Errorf("execution.search.slo.minAchievedRatio must be between 0 and 1")
+	}
+	return nil
+}
+
+// isStaged reports whether the mode follows execution.stages.
+func (c *Config) isStaged() bool {
+	switch strings.ToLower(c.Execution.Mode) {
+	case "ramp":
+		return true
+	case "concurrency":
+		return len(c.Execution.Stages) > 0
+	}
+	return false
+}
+
+// peakLoad is the highest rate (or user count in concurrency mode) the run
+// will target, used to size the worker pool and connection limits.
+func (c *Config) peakLoad() int {
+	if strings.EqualFold(c.Execution.Mode, "concurrency") && len(c.Execution.Stages) == 0 {
+		return c.Execution.VirtualUsers
+	}
+	if strings.EqualFold(c.Execution.Mode, "search") {
+		return c.Execution.Search.MaxRPS
+	}
+	if !c.isStaged() {
+		return c.Execution.RequestsPerSecond
+	}
+	peak := 0
+	for _, st := range c.Execution.Stages {
+		start, target := st.Bounds(c.Execution.Mode)
+		peak = max(peak, start, target)
+	}
+	return peak
+}
+
+// PeakUsers is the most virtual users a concurrency-mode run keeps at once;
+// 0 in the other modes.
+func (c *Config) PeakUsers() int {
+	if !strings.EqualFold(c.Execution.Mode, "concurrency") {
+		return 0
+	}
+	return c.peakLoad()
+}
+
+// validateStages checks the ramp or concurrency profile.
+func (c *Config) validateStages() error {
+	mode := strings.ToLower(c.Execution.Mode)
+	if len(c.Execution.Stages) == 0 {
+		return fmt.Errorf("execution.stages must not be empty for %s mode", mode)
+	}
+	startKey, targetKey := "startRps", "targetRps"
+	if mode == "concurrency" {
+		startKey, targetKey = "startUsers", "targetUsers"
+	}
+	seen := make(map[string]bool, len(c.Execution.Stages))
+	for i, st := range c.Execution.Stages {
+		if seen[st.Name] {
+			return fmt.Errorf("execution.stages[%d]: duplicate stage name %q", i, st.Name)
+		}
+		seen[st.Name] = true
+		if st.DurationSeconds <= 0 {
+			return fmt.Errorf("execution.stages[%d] (%s): durationSeconds must be positive", i, st.Name)
+		}
+		start, target := st.Bounds(mode)
+		if start < 0 {
+			return fmt.Errorf("execution.stages[%d] (%s): %s must not be negative", i, st.Name, startKey)
+		}
+		if target <= 0 {
+			return fmt.Errorf("execution.stages[%d] (%s): %s must be positive", i, st.Name, targetKey)
+		}
+		if mode == "concurrency" && max(start, target) > maxWorkersCap {
+			return fmt.Errorf("execution.stages[%d] (%s): virtual users must not exceed %d", i, st.Name, maxWorkersCap)
+		}
+		switch st.Curve {
+		case CurveLinear, CurveStep:
+		case CurveExponential:
+			if start <= 0 {
+				return fmt.Errorf("execution.stages[%d] (%s): exponential curve needs a positive %s", i, st.Name, startKey)
+			}
+		default:
+			return fmt.Errorf("execution.stages[%d] (%s): unknown curve %q", i, st.Name, st.Curve)
+		}
+	}
+	return nil
 }
 
 // Validate checks configuration after defaults and generator registration.
@@ -167,7 +590,25 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("requestsPerSecond must be positive for fixed mode")
 		}
 	case "ramp":
-		return fmt.Errorf("execution.mode %q is not implemented yet", c.Execution.Mode)
+		if err := c.validateStages(); err != nil {
+			return err
+		}
+	case "concurrency":
+		if len(c.Execution.Stages) > 0 {
+			if err := c.validateStages(); err != nil {
+				return err
+			}
+		} else if c.Execution.VirtualUsers < 1 || c.Execution.VirtualUsers > maxWorkersCap {
+			return fmt.Errorf("execution.virtualUsers must be between 1 and %d for concurrency mode", maxWorkersCap)
+		}
+		if c.Execution.ThinkTimeMs < 0 {
+			return fmt.Errorf("execution.thinkTimeMs must not be negative")
+		}
+	case "max":
+	case "search":
+		if err := c.validateSearch(); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unknown execution.mode %q", c.Execution.Mode)
 	}
@@ -186,6 +627,21 @@ func (c *Config) Validate() error {
 	if c.Execution.RateBurst < 1 || c.Execution.RateBurst > maxRateBurstCap {
 		return fmt.Errorf("execution.rateBurst must be between 1 and %d", maxRateBurstCap)
 	}
+	if err := c.Execution.Arrival.validate(c.dir); err != nil {
+		return err
+	}
+	if err := c.Metrics.validate(); err != nil {
+		return err
+	}
+	if err := c.Thresholds.validate(c.Endpoints); err != nil {
+		return err
+	}
+	if err := c.Tracing.validate(); err != nil {
+		return err
+	}
+	if err := c.Report.validate(c.dir); err != nil {
+		return err
+	}
 	strat := strings.ToLower(c.EndpointSelection.Strategy)
 	switch strat {
 	case "weighted", "roundrobin", "random":
@@ -29,11 +29,239 @@ func TestValidate_WeightedUnknownEndpoint(t *testing.T) {
 	}
 }
 
-func TestValidate_RampNotImplemented(t *testing.T) {
+func TestValidate_RampRequiresStages(t *testing.T) {
 	c := minimalValidConfig()
 	c.Execution.Mode = "ramp"
-	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "not implemented") {
-		t.Fatalf("expected ramp not implemented, got %v", err)
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "stages") {
+		t.Fatalf("expected missing stages error, got %v", err)
+	}
+}
+
+func TestValidate_RampStageDefaults(t *testing.T) {
+	c := minimalValidConfig()
+	c.Execution.Mode = "Ramp"
+	c.Execution.MaxWorkers = 0
+	c.Execution.Stages = []StageConfig{
+		{StartRPS: 0, TargetRPS: 50, DurationSeconds: 10},
+		{Name: "hold", StartRPS: 50, TargetRPS: 50, DurationSeconds: 20, Curve: "Step"},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Execution.Mode != "ramp" {
+		t.Fatalf("expected mode to be normalised, got %q", c.Execution.Mode)
+	}
+	if c.Execution.Stages[0].Name != "stage-1" || c.Execution.Stages[0].Curve != CurveLinear {
+		t.Fatalf("unexpected stage defaults: %+v", c.Execution.Stages[0])
+	}
+	if c.Execution.Stages[1].Curve != CurveStep {
+		t.Fatalf("expected curve to be normalised, got %q", c.Execution.Stages[1].Curve)
+	}
+	if c.Execution.DurationSeconds != 30 {
+		t.Fatalf("expected duration derived from stages (30), got %d", c.Execution.DurationSeconds)
+	}
+	if c.Execution.MaxWorkers != 50 {
+		t.Fatalf("expected auto maxWorkers from peak stage RPS (50), got %d", c.Execution.MaxWorkers)
+	}
+}
+
+func TestValidate_RampInvalidStages(t *testing.T) {
+	testCases := []struct {
+		name  string
+		stage StageConfig
+		want  string
+	}{
+		{"zero duration", StageConfig{TargetRPS: 10}, "durationSeconds"},
+		{"zero target", StageConfig{DurationSeconds: 5}, "targetRps"},
+		{"negative start", StageConfig{StartRPS: -1, TargetRPS: 10, DurationSeconds: 5}, "startRps"},
+		{"exponential from zero", StageConfig{TargetRPS: 10, DurationSeconds: 5, Curve: "exponential"}, "exponential"},
+		{"unknown curve", StageConfig{TargetRPS: 10, DurationSeconds: 5, Curve: "sine"}, "unknown curve"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := minimalValidConfig()
+			c.Execution.Mode = "ramp"
+			c.Execution.Stages = []StageConfig{tc.stage}
+			if err := c.Validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_ConcurrencyMode(t *testing.T) {
+	c := minimalValidConfig()
+	c.Execution.Mode = "concurrency"
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "virtualUsers") {
+		t.Fatalf("expected virtualUsers error, got %v", err)
+	}
+
+	c.Execution.VirtualUsers = 20
+	c.Execution.MaxWorkers = 0
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Execution.MaxWorkers != 20 {
+		t.Fatalf("expected maxWorkers sized from virtual users (20), got %d", c.Execution.MaxWorkers)
+	}
+
+	c.Execution.ThinkTimeMs = -1
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "thinkTimeMs") {
+		t.Fatalf("expected thinkTimeMs error, got %v", err)
+	}
+}
+
+func TestValidate_ConcurrencyStages(t *testing.T) {
+	c := minimalValidConfig()
+	c.Execution.Mode = "concurrency"
+	c.Execution.Stages = []StageConfig{{StartRPS: 1, TargetRPS: 10, DurationSeconds: 5}}
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "targetUsers") {
+		t.Fatalf("expected targetUsers error, got %v", err)
+	}
+
+	c.Execution.Stages = []StageConfig{
+		{StartUsers: 1, TargetUsers: 10, DurationSeconds: 5},
+		{TargetUsers: 10, DurationSeconds: 15, Curve: CurveStep},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Execution.DurationSeconds != 20 {
+		t.Fatalf("expected duration derived from stages (20), got %d", c.Execution.DurationSeconds)
+	}
+}
+
+func TestValidate_MaxModeDefaultWorkers(t *testing.T) {
+	c := minimalValidConfig()
+	c.Execution.Mode = "max"
+	c.Execution.MaxWorkers = 0
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Execution.MaxWorkers != defaultMaxModeWorkers {
+		t.Fatalf("expected default max-mode workers %d, got %d", defaultMaxModeWorkers, c.Execution.MaxWorkers)
+	}
+}
+
+func TestValidate_SearchDefaults(t *testing.T) {
+	c := minimalValidConfig()
+	c.Execution.Mode = "search"
+	c.Execution.MaxWorkers = 0
+	c.Execution.Search = SearchConfig{StartRPS: 100, MaxRPS: 1100, StepDurationSeconds: 5}
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	sc := c.Execution.Search
+	if sc.Strategy != SearchStep || sc.StepRPS != 100 || sc.PrecisionRPS != 31 {
+		t.Fatalf("unexpected search defaults: %+v", sc)
+	}
+	if sc.SLO.MinAchievedRatio != 0.9 {
+		t.Fatalf("expected default minAchievedRatio 0.9, got %v", sc.SLO.MinAchievedRatio)
+	}
+	if c.Execution.DurationSeconds != 11*5 {
+		t.Fatalf("expected worst-case duration of 11 steps x 5s, got %d", c.Execution.DurationSeconds)
+	}
+	if c.Execution.MaxWorkers != 256 {
+		t.Fatalf("expected maxWorkers sized from maxRps, got %d", c.Execution.MaxWorkers)
+	}
+}
+
+func TestValidate_SearchInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		search SearchConfig
+		want   string
+	}{
+		{"unknown strategy", SearchConfig{Strategy: "golden", StartRPS: 1, MaxRPS: 10, StepDurationSeconds: 1}, "strategy"},
+		{"no start", SearchConfig{MaxRPS: 10, StepDurationSeconds: 1}, "startRps"},
+		{"max below start", SearchConfig{StartRPS: 10, MaxRPS: 10, StepDurationSeconds: 1}, "maxRps"},
+		{"no step duration", SearchConfig{StartRPS: 1, MaxRPS: 10}, "stepDurationSeconds"},
+		{"error rate as percent", SearchConfig{StartRPS: 1, MaxRPS: 10, StepDurationSeconds: 1, SLO: SLOConfig{MaxErrorRate: 5}}, "maxErrorRate"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := minimalValidConfig()
+			c.Execution.Mode = "search"
+			c.Execution.Search = tc.search
+			if err := c.Validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_Metrics(t *testing.T) {
+	testCases := []struct {
+		name    string
+		metrics MetricsConfig
+		want    string
+	}{
+		{"defaults", MetricsConfig{}, ""},
+		{"custom", MetricsConfig{Percentiles: []float64{50, 99.9, 100}, HistogramPrecision: 4}, ""},
+		{"zero percentile", MetricsConfig{Percentiles: []float64{0}}, "metrics.percentiles"},
+		{"fraction instead of percent", MetricsConfig{Percentiles: []float64{101}}, "metrics.percentiles"},
+		{"precision too high", MetricsConfig{HistogramPrecision: 6}, "histogramPrecision"},
+		{"negative sample size", MetricsConfig{SampleSize: -1}, "sampleSize"},
+		{"window too wide", MetricsConfig{WindowSeconds: 7200}, "windowSeconds"},
+		{"influx url", MetricsConfig{Influx: InfluxConfig{URL: "http://influx:8086/api/v2/write?bucket=b", Tags: map[string]string{"env": "ci"}}}, ""},
+		{"influx url and file", MetricsConfig{Influx: InfluxConfig{URL: "http://influx:8086", File: "points.lp"}}, "either url or file"},
+		{"influx url without scheme", MetricsConfig{Influx: InfluxConfig{URL: "influx:8086/write"}}, "metrics.influx.url"},
+		{"influx reserved tag", MetricsConfig{Influx: InfluxConfig{File: "points.lp", Tags: map[string]string{"endpoint": "x"}}}, "reserved"},
+		{"influx empty tag value", MetricsConfig{Influx: InfluxConfig{File: "points.lp", Tags: map[string]string{"env": ""}}}, "empty value"},
+		{"influx negative interval", MetricsConfig{Influx: InfluxConfig{File: "points.lp", IntervalSeconds: -1}}, "intervalSeconds"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := minimalValidConfig()
+			c.Metrics = tc.metrics
+			err := c.Validate()
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestValidate_Tracing(t *testing.T) {
+	c := minimalValidConfig()
+	c.Tracing = TracingConfig{OTLPEndpoint: "http://collector:4318/v1/traces", DisablePropagation: true}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Tracing.OTLPEndpoint = "collector:4318"
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "tracing.otlpEndpoint") {
+		t.Fatalf("expected an otlpEndpoint error, got %v", err)
+	}
+}
+
+func TestValidate_Report(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "summary.tmpl"), []byte("{{.Results.Requests}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c := minimalValidConfig()
+	c.dir = dir
+	c.Report = ReportConfig{Template: "summary.tmpl", Output: "summary.txt"}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := c.Report.TemplatePath(); got != filepath.Join(dir, "summary.tmpl") {
+		t.Errorf("template should resolve against the config directory, got %q", got)
+	}
+
+	c.Report.Template = "missing.tmpl"
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "report.template") {
+		t.Fatalf("expected a report.template error, got %v", err)
+	}
+	c.Report.Template = ""
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "report.output") {
+		t.Fatalf("expected a report.output error, got %v", err)
 	}
 }
 
@@ -150,3 +378,91 @@ func minimalValidConfig() *Config {
 		EndpointSelection: EndpointSelectionConfig{Strategy: "roundRobin"},
 	}
 }
+
+func TestLoadConfig_Overrides(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cfg.yaml")
+	yaml := `
+baseUrls: ["http://127.0.0.1:9"]
+execution:
+  mode: ramp
+  requestTimeoutMs: 1000
+  stages:
+    - {targetRps: 10, durationSeconds: 5}
+endpoints:
+  ep:
+    path: "/"
+    method: POST
+    bodyParameters:
+      user: {name: "ada"}
+`
+	if err := os.WriteFile(path, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var overrides []Override
+	for _, s := range []string{
+		"execution.stages.0.targetRps=50",
+		"baseUrls=[http://a:8080, http://b:8080]",
+		"endpoints.ep.headers.X-Env=staging",
+		"endpoints.ep.bodyParameters.user.age=36",
+		"thresholds.maxErrorRate=0.01",
+		"metrics.percentiles=[50, 99.9]",
+	} {
+		o, err := ParseOverride(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		overrides = append(overrides, o)
+	}
+	cfg, err := LoadConfig(path, overrides...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Execution.Stages[0].TargetRPS != 50 || cfg.Execution.DurationSeconds != 5 {
+		t.Errorf("stage override not applied before defaults: %+v", cfg.Execution)
+	}
+	if len(cfg.BaseUrls) != 2 || cfg.BaseUrls[1] != "http://b:8080" {
+		t.Errorf("unexpected base URLs %v", cfg.BaseUrls)
+	}
+	ep := cfg.Endpoints["ep"]
+	if ep.Headers["X-Env"] != "staging" || ep.Method != "POST" {
+		t.Errorf("unexpected endpoint %+v", ep)
+	}
+	if user := ep.BodyParameters.(map[any]any)["user"].(map[any]any); user["name"] != "ada" || user["age"] != 36 {
+		t.Errorf("unexpected body parameters %v", ep.BodyParameters)
+	}
+	if cfg.Thresholds.MaxErrorRate == nil || *cfg.Thresholds.MaxErrorRate != 0.01 || len(cfg.Metrics.Percentiles) != 2 {
+		t.Errorf("unexpected thresholds %+v / percentiles %v", cfg.Thresholds, cfg.Metrics.Percentiles)
+	}
+
+	for _, tc := range []struct {
+		override Override
+		want     string
+	}{
+		{Override{Path: "execution.requestPerSecond", Value: 5}, "unknown config key"},
+		{Override{Path: "execution.requestsPerSecond", Value: "fast"}, "cannot unmarshal"},
+		{Override{Path: "execution.stages.3.targetRps", Value: 5}, "not an index"},
+		{Override{Path: "execution.mode.name", Value: "x"}, "single value"},
+		{Override{Path: "execution.stages.0.durationSeconds", Value: 0}, "durationSeconds"}, // Validated after overriding
+		{Override{Path: "execution.durationSeconds", Value: 60}, "takes its duration from the stages"},
+	} {
+		if _, err := LoadConfig(path, tc.override); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s=%v: expected an error containing %q, got %v", tc.override.Path, tc.override.Value, tc.want, err)
+		}
+	}
+}
+
+func TestParseOverride(t *testing.T) {
+	o, err := ParseOverride("execution.requestsPerSecond=500")
+	if err != nil || o.Path != "execution.requestsPerSecond" || o.Value != 500 {
+		t.Fatalf("got %+v, %v", o, err)
+	}
+	if o, err = ParseOverride("output.json="); err != nil || o.Value != nil {
+		t.Fatalf("an empty value should clear the setting, got %+v, %v", o, err)
+	}
+	for _, s := range []string{"execution.mode", "=fixed", "baseUrls=[a"} {
+		if _, err := ParseOverride(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Override replaces one config value, addressed by its dotted YAML path
+// (e.g. execution.requestsPerSecond), before the config is validated
+type Override struct {
+	Path  string
+	Value any
+}
+
+// ParseOverride parses "path=value"; the value is read as YAML, so numbers,
+// booleans and flow lists such as [a, b] keep their type
+func ParseOverride(s string) (Override, error) {
+	path, raw, ok := strings.Cut(s, "=")
+	path = strings.TrimSpace(path)
+	if !ok || path == "" {
+		return Override{}, fmt.Errorf("override %q must look like path=value", s)
+	}
+	var value any
+	if err := yaml.Unmarshal([]byte(raw), &value); err != nil {
+		return Override{}, fmt.Errorf("override %s: invalid value %q: %w", path, raw, err)
+	}
+	return Override{Path: path, Value: value}, nil
+}
+
+// apply sets the override in cfg. Paths that name no config field are
+// rejected, so a typo fails instead of being ignored like an unknown YAML key.
+func (o Override) apply(cfg *Config) error {
+	keys := strings.Split(o.Path, ".")
+	if err := setField(reflect.ValueOf(cfg).Elem(), keys, 0, o.Value); err != nil {
+		return fmt.Errorf("override %s: %w", o.Path, err)
+	}
+	return nil
+}
+
+// checkDurationOverride rejects a durationSeconds override in the modes that
+// derive the duration from their stages or search steps, where it would be
+// silently replaced
+func (c *Config) checkDurationOverride(overrides []Override) error {
+	source := ""
+	switch {
+	case strings.EqualFold(c.Execution.Mode, "search"):
+		source = "search steps"
+	case c.isStaged():
+		source = "stages"
+	default:
+		return nil
+	}
+	for _, o := range overrides {
+		if o.Path == "execution.durationSeconds" {
+			return fmt.Errorf("override %s: %s mode takes its duration from the %s; change those instead", o.Path, c.Execution.Mode, source)
+		}
+	}
+	return nil
+}
+
+// setField sets value at keys[i:] below v, creating missing maps and pointers
+func setField(v reflect.Value, keys []string, i int, value any) error {
+	if i == len(keys) {
+		return assign(v, value)
+	}
+	key := keys[i]
+	if key == "" {
+		return fmt.Errorf("empty path element")
+	}
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setField(v.Elem(), keys, i, value)
+	case reflect.Struct:
+		field, ok := yamlField(v, key)
+		if !ok {
+			return fmt.Errorf("unknown config key %q", strings.Join(keys[:i+1], "."))
+		}
+		return setField(field, keys, i+1, value)
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		k := reflect.ValueOf(key).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(k); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setField(elem, keys, i+1, value); err != nil {
+			return err
+		}
+		v.SetMapIndex(k, elem)
+		return nil
+	case reflect.Slice:
+		n, err := strconv.Atoi(key)
+		if err != nil || n < 0 || n >= v.Len() {
+			return fmt.Errorf("%s has %d entries; %q is not an index", strings.Join(keys[:i], "."), v.Len(), key)
+		}
+		return setField(v.Index(n), keys, i+1, value)
+	case reflect.Interface:
+		// Free-form values such as bodyParameters hold decoded YAML
+		var current any
+		if !v.IsNil() {
+			current = v.Interface()
+		}
+		updated, err := setPath(current, keys[i:], value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(&updated).Elem())
+		return nil
+	default:
+		return fmt.Errorf("%s is a single value and has no key %q", strings.Join(keys[:i], "."), key)
+	}
+}
+
+// assign stores value in v, decoding it like the same value in the config file
+func assign(v reflect.Value, value any) error {
+	if v.Kind() == reflect.Interface {
+		v.Set(reflect.ValueOf(&value).Elem())
+		return nil
+	}
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+	decoded := reflect.New(v.Type())
+	if err := yaml.Unmarshal(data, decoded.Interface()); err != nil {
+		return err
+	}
+	v.Set(decoded.Elem())
+	return nil
+}
+
+// yamlField returns the field of struct v named key in YAML, looking through
+// inline structs
+func yamlField(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			if field, ok := yamlField(v.Field(i), key); ok {
+				return field, true
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if name == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// setPath sets value at keys inside decoded YAML, creating missing mappings
+func setPath(node any, keys []string, value any) (any, error) {
+	if len(keys) == 0 {
+		return value, nil
+	}
+	key, rest := keys[0], keys[1:]
+	switch n := node.(type) {
+	case nil:
+		child, err := setPath(nil, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		return map[any]any{key: child}, nil
+	case map[any]any:
+		k := mapKey(n, key)
+		child, err := setPath(n[k], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		n[k] = child
+		return n, nil
+	case []any:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(n) {
+			return nil, fmt.Errorf("list has %d entries; %q is not an index", len(n), key)
+		}
+		if n[i], err = setPath(n[i], rest, value); err != nil {
+			return nil, err
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q inside the value %v", key, n)
+	}
+}
+
+// mapKey returns the key of m spelled key; YAML may have decoded it as a
+// number or boolean
+func mapKey(m map[any]any, key string) any {
+	if _, ok := m[key]; ok {
+		return key
+	}
+	for k := range m {
+		if fmt.Sprint(k) == key {
+			return k
+		}
+	}
+	return key
+}
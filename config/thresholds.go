@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ThresholdSet lists the limits checked against one scope of the results.
+// Unset limits are not checked.
+type ThresholdSet struct {
+	// MaxErrorRate is the highest allowed failed fraction, 0..1 (0 = no errors allowed).
+	MaxErrorRate *float64 `yaml:"maxErrorRate,omitempty" json:"maxErrorRate,omitempty"`
+	// Percentiles maps a percentile label to the highest allowed service time in ms, e.g. p95: 200.
+	Percentiles map[string]float64 `yaml:"percentiles,omitempty" json:"percentiles,omitempty"`
+	// MinRPS is the lowest acceptable achieved request rate.
+	MinRPS *float64 `yaml:"minRps,omitempty" json:"minRps,omitempty"`
+}
+
+// ThresholdsConfig holds the pass/fail criteria evaluated after a run: the
+// inline limits apply to all requests, Endpoints to single endpoints.
+type ThresholdsConfig struct {
+	ThresholdSet `yaml:",inline"`
+	// MaxDropped is the most scheduled requests that may be dropped because the queue was full.
+	MaxDropped *int64 `yaml:"maxDroppedRequests,omitempty" json:"maxDroppedRequests,omitempty"`
+	// Endpoints holds limits for single endpoints, keyed by endpoint name.
+	Endpoints map[string]ThresholdSet `yaml:"endpoints,omitempty" json:"endpoints,omitempty"`
+}
+
+// PercentileLimit is one parsed entry of ThresholdSet.Percentiles
+type PercentileLimit struct {
+	Label      string  // As written in the config, e.g. "p99.9"
+	Percentile float64 // 0 < p <= 100
+	MaxMs      float64
+}
+
+// IsEmpty reports whether no threshold is configured
+func (t ThresholdsConfig) IsEmpty() bool {
+	return t.ThresholdSet.isEmpty() && t.MaxDropped == nil && len(t.Endpoints) == 0
+}
+
+func (s ThresholdSet) isEmpty() bool {
+	return s.MaxErrorRate == nil && len(s.Percentiles) == 0 && s.MinRPS == nil
+}
+
+// PercentileLimits returns the percentile limits ordered by percentile.
+// Keys that do not parse are skipped; Validate reports them.
+func (s ThresholdSet) PercentileLimits() []PercentileLimit {
+	limits := make([]PercentileLimit, 0, len(s.Percentiles))
+	for label, ms := range s.Percentiles {
+		p, err := parsePercentileLabel(label)
+		if err != nil {
+			continue
+		}
+		limits = append(limits, PercentileLimit{Label: label, Percentile: p, MaxMs: ms})
+	}
+	sort.Slice(limits, func(i, j int) bool { return limits[i].Percentile < limits[j].Percentile })
+	return limits
+}
+
+// parsePercentileLabel reads "p95" or "p99.9" as 95 or 99.9
+func parsePercentileLabel(label string) (float64, error) {
+	num, ok := strings.CutPrefix(strings.ToLower(label), "p")
+	if !ok {
+		return 0, fmt.Errorf("percentile %q must look like p95", label)
+	}
+	p, err := strconv.ParseFloat(num, 64)
+	if err != nil || p <= 0 || p > 100 {
+		return 0, fmt.Errorf("percentile %q must be in (p0, p100]", label)
+	}
+	return p, nil
+}
+
+// validate checks the limits of one scope; name prefixes error messages
+func (s ThresholdSet) validate(name string) error {
+	if s.MaxErrorRate != nil && (*s.MaxErrorRate < 0 || *s.MaxErrorRate > 1) {
+		return fmt.Errorf("%s.maxErrorRate must be between 0 and 1", name)
+	}
+	if s.MinRPS != nil && *s.MinRPS <= 0 {
+		return fmt.Errorf("%s.minRps must be positive", name)
+	}
+	for label, ms := range s.Percentiles {
+		if _, err := parsePercentileLabel(label); err != nil {
+			return fmt.Errorf("%s.percentiles: %w", name, err)
+		}
+		if ms <= 0 {
+			return fmt.Errorf("%s.percentiles.%s must be a positive number of ms", name, label)
+		}
+	}
+	return nil
+}
+
+// validate checks every scope and that per-endpoint limits name known endpoints
+func (t ThresholdsConfig) validate(endpoints map[string]EndpointConfig) error {
+	if err := t.ThresholdSet.validate("thresholds"); err != nil {
+		return err
+	}
+	if t.MaxDropped != nil && *t.MaxDropped < 0 {
+		return fmt.Errorf("thresholds.maxDroppedRequests must not be negative")
+	}
+	for name, set := range t.Endpoints {
+		if _, ok := endpoints[name]; !ok {
+			return fmt.Errorf("thresholds.endpoints: unknown endpoint %q", name)
+		}
+		if err := set.validate("thresholds.endpoints." + name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
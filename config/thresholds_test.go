@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig_Thresholds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	yaml := `
+baseUrls: ["http://127.0.0.1:9"]
+execution:
+  mode: fixed
+  requestsPerSecond: 10
+endpoints:
+  get_user:
+    path: "/users/1"
+    method: "GET"
+thresholds:
+  maxErrorRate: 0.01
+  percentiles:
+    p99: 500
+    p95: 200
+  endpoints:
+    get_user:
+      percentiles:
+        p99.9: 800
+`
+	if err := os.WriteFile(path, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	th := cfg.Thresholds
+	if th.MaxErrorRate == nil || *th.MaxErrorRate != 0.01 || th.IsEmpty() {
+		t.Fatalf("global thresholds not loaded: %+v", th)
+	}
+	limits := th.PercentileLimits()
+	if len(limits) != 2 || limits[0].Percentile != 95 || limits[0].MaxMs != 200 || limits[1].Label != "p99" {
+		t.Fatalf("expected p95 then p99 limits, got %+v", limits)
+	}
+	ep := th.Endpoints["get_user"].PercentileLimits()
+	if len(ep) != 1 || ep[0].Percentile != 99.9 {
+		t.Fatalf("unexpected endpoint limits %+v", ep)
+	}
+}
+
+func TestValidate_Thresholds(t *testing.T) {
+	rate := func(v float64) *float64 { return &v }
+	count := func(v int64) *int64 { return &v }
+	testCases := []struct {
+		name       string
+		thresholds ThresholdsConfig
+		want       string
+	}{
+		{"none", ThresholdsConfig{}, ""},
+		{"zero error rate", ThresholdsConfig{ThresholdSet: ThresholdSet{MaxErrorRate: rate(0)}}, ""},
+		{"error rate as percent", ThresholdsConfig{ThresholdSet: ThresholdSet{MaxErrorRate: rate(5)}}, "thresholds.maxErrorRate"},
+		{"bad label", ThresholdsConfig{ThresholdSet: ThresholdSet{Percentiles: map[string]float64{"95": 200}}}, "thresholds.percentiles"},
+		{"percentile out of range", ThresholdsConfig{ThresholdSet: ThresholdSet{Percentiles: map[string]float64{"p101": 200}}}, "thresholds.percentiles"},
+		{"non-positive limit", ThresholdsConfig{ThresholdSet: ThresholdSet{Percentiles: map[string]float64{"p95": 0}}}, "thresholds.percentiles.p95"},
+		{"zero min rps", ThresholdsConfig{ThresholdSet: ThresholdSet{MinRPS: rate(0)}}, "thresholds.minRps"},
+		{"negative dropped", ThresholdsConfig{MaxDropped: count(-1)}, "maxDroppedRequests"},
+		{"unknown endpoint", ThresholdsConfig{Endpoints: map[string]ThresholdSet{"b": {}}}, "unknown endpoint"},
+		{"bad endpoint limit", ThresholdsConfig{Endpoints: map[string]ThresholdSet{"a": {MaxErrorRate: rate(-1)}}}, "thresholds.endpoints.a.maxErrorRate"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := minimalValidConfig()
+			c.Thresholds = tc.thresholds
+			err := c.Validate()
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,9 @@ module benchmarking-tool
 go 1.25.0
 
 require (
+	golang.org/x/term v0.36.0
 	golang.org/x/time v0.15.0
 	gopkg.in/yaml.v2 v2.4.0
 )
+
+require golang.org/x/sys v0.37.0 // indirect
@@ -0,0 +1,181 @@
+package influx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+)
+
+// DefaultMeasurement names the points when none is configured
+const DefaultMeasurement = "benchmark"
+
+// requestTimeout bounds one HTTP write so a hung endpoint cannot stall the pusher forever
+const requestTimeout = 10 * time.Second
+
+// Writer is a metrics.ResultsSink that writes windows in InfluxDB line
+// protocol, either POSTed to an HTTP write endpoint or appended to a file.
+type Writer struct {
+	measurement string
+	tags        string // Configured tags, pre-rendered as ",k=v" pairs
+	url         string
+	token       string
+	client      *http.Client
+	file        *os.File
+}
+
+// NewWriter creates a writer for cfg, opening (and truncating) the file if one is set
+func NewWriter(cfg config.InfluxConfig) (*Writer, error) {
+	w := &Writer{
+		measurement: cfg.Measurement,
+		tags:        renderTags(cfg.Tags),
+		url:         cfg.URL,
+		token:       cfg.Token,
+		client:      &http.Client{Timeout: requestTimeout},
+	}
+	if w.measurement == "" {
+		w.measurement = DefaultMeasurement
+	}
+	if cfg.File != "" {
+		f, err := os.Create(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create InfluxDB line protocol file: %w", err)
+		}
+		w.file = f
+	}
+	return w, nil
+}
+
+// WriteWindows sends one batch
+func (w *Writer) WriteWindows(batch []metrics.WindowPoint) error {
+	var buf bytes.Buffer
+	for _, p := range batch {
+		w.writePoint(&buf, p)
+	}
+	if w.file != nil {
+		if _, err := w.file.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write line protocol: %w", err)
+		}
+		return nil
+	}
+	return w.post(buf.Bytes())
+}
+
+// Close closes the output file, if any
+func (w *Writer) Close() error {
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+func (w *Writer) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build InfluxDB write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.token != "" {
+		req.Header.Set("Authorization", "Token "+w.token)
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send metrics to %s: %w", w.url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("metrics endpoint %s returned %s: %s", w.url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// writePoint renders one window as a line:
+// measurement,scope=...,endpoint=...,tags fields timestamp
+func (w *Writer) writePoint(buf *bytes.Buffer, p metrics.WindowPoint) {
+	res := p.Window.Results
+	buf.WriteString(escape(w.measurement, ", "))
+	if p.Endpoint == "" {
+		buf.WriteString(",scope=total")
+	} else {
+		buf.WriteString(",scope=endpoint,endpoint=")
+		buf.WriteString(escape(p.Endpoint, ",= "))
+	}
+	buf.WriteString(w.tags)
+
+	fields := []string{
+		"requests=" + strconv.FormatInt(res.TotalRequests, 10) + "i",
+		"failed=" + strconv.FormatInt(res.FailedRequests, 10) + "i",
+		"error_rate=" + formatFloat(errorRate(res)),
+		"rps=" + formatFloat(p.Window.AchievedRPS),
+		"avg_ms=" + formatFloat(ms(res.AvgDuration)),
+		"max_ms=" + formatFloat(ms(res.MaxDuration)),
+	}
+	for _, pv := range res.Percentiles {
+		fields = append(fields, metrics.PercentileLabel(pv.Percentile)+"_ms="+formatFloat(ms(pv.Value)))
+	}
+	for _, pv := range res.ResponsePercentiles {
+		fields = append(fields, "response_"+metrics.PercentileLabel(pv.Percentile)+"_ms="+formatFloat(ms(pv.Value)))
+	}
+	fields = append(fields,
+		"bytes_sent="+strconv.FormatInt(res.BytesSent, 10)+"i",
+		"bytes_received="+strconv.FormatInt(res.BytesReceived, 10)+"i",
+	)
+	buf.WriteByte(' ')
+	buf.WriteString(strings.Join(fields, ","))
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(p.Window.Start.UnixNano(), 10))
+	buf.WriteByte('\n')
+}
+
+// renderTags formats tags sorted by key, as InfluxDB recommends
+func renderTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, ",%s=%s", escape(k, ",= "), escape(tags[k], ",= "))
+	}
+	return b.String()
+}
+
+// escape backslash-escapes the given special characters
+func escape(s, special string) string {
+	if !strings.ContainsAny(s, special) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func errorRate(res metrics.AggregatedResults) float64 {
+	if res.TotalRequests == 0 {
+		return 0
+	}
+	return float64(res.FailedRequests) / float64(res.TotalRequests)
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
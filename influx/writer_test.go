@@ -0,0 +1,127 @@
+package influx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+)
+
+func testBatch() []metrics.WindowPoint {
+	start := time.Unix(1700000000, 0)
+	window := func(endpoint string, requests, failed int64) metrics.WindowPoint {
+		return metrics.WindowPoint{Endpoint: endpoint, Window: metrics.WindowResults{
+			Start: start, Duration: time.Second, AchievedRPS: float64(requests),
+			Results: metrics.AggregatedResults{
+				TotalRequests: requests, FailedRequests: failed,
+				AvgDuration: 12500 * time.Microsecond, MaxDuration: 40 * time.Millisecond,
+				Percentiles:         []metrics.PercentileValue{{Percentile: 50, Value: 10 * time.Millisecond}, {Percentile: 99.9, Value: 40 * time.Millisecond}},
+				ResponsePercentiles: []metrics.PercentileValue{{Percentile: 50, Value: 11 * time.Millisecond}},
+				BytesSent:           100, BytesReceived: 2048,
+			},
+		}}
+	}
+	return []metrics.WindowPoint{window("", 8, 2), window("get user", 4, 0)}
+}
+
+func TestWriter_HTTP(t *testing.T) {
+	var body, auth, contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body, auth, contentType = string(b), r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w, err := NewWriter(config.InfluxConfig{URL: srv.URL + "/api/v2/write?bucket=b", Token: "secret", Tags: map[string]string{"env": "ci", "run id": "a,b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteWindows(testBatch()); err != nil {
+		t.Fatal(err)
+	}
+	want := "benchmark,scope=total,env=ci,run\\ id=a\\,b requests=8i,failed=2i,error_rate=0.25,rps=8,avg_ms=12.5,max_ms=40," +
+		"p50_ms=10,p99.9_ms=40,response_p50_ms=11,bytes_sent=100i,bytes_received=2048i 1700000000000000000\n" +
+		"benchmark,scope=endpoint,endpoint=get\\ user,env=ci,run\\ id=a\\,b requests=4i,failed=0i,error_rate=0,rps=4,avg_ms=12.5,max_ms=40," +
+		"p50_ms=10,p99.9_ms=40,response_p50_ms=11,bytes_sent=100i,bytes_received=2048i 1700000000000000000\n"
+	if body != want {
+		t.Errorf("unexpected body:\n%s\nwant:\n%s", body, want)
+	}
+	if auth != "Token secret" || !strings.HasPrefix(contentType, "text/plain") {
+		t.Errorf("unexpected headers: Authorization %q, Content-Type %q", auth, contentType)
+	}
+}
+
+func TestWriter_HTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bucket not found", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	w, err := NewWriter(config.InfluxConfig{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.WriteWindows(testBatch())
+	if err == nil || !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "bucket not found") {
+		t.Errorf("expected the status and message in the error, got %v", err)
+	}
+}
+
+func TestWriter_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "points.lp")
+	w, err := NewWriter(config.InfluxConfig{File: path, Measurement: "load test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := w.WriteWindows(testBatch()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "load\\ test,scope=total ") {
+		t.Errorf("expected batches appended to the file, got:\n%s", data)
+	}
+}
+
+func TestWriter_WithPusher(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		requests = append(requests, string(b))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w, err := NewWriter(config.InfluxConfig{URL: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := metrics.NewPusher(w, metrics.PushOptions{Interval: time.Hour})
+	p.Start()
+	for range 3 {
+		p.Record(metrics.MetricDetail{Endpoint: "health", StatusCode: 200, Duration: time.Millisecond, Timestamp: time.Now()})
+	}
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	all := strings.Join(requests, "")
+	if !strings.Contains(all, "scope=endpoint,endpoint=health") || strings.Count(all, "requests=") < 2 {
+		t.Errorf("expected the pushed windows, got %q", all)
+	}
+}
@@ -1,28 +1,252 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
+	"benchmarking-tool/compare"
 	"benchmarking-tool/config"
+	"benchmarking-tool/influx"
 	"benchmarking-tool/metrics"
+	"benchmarking-tool/otlp"
+	"benchmarking-tool/prometheus"
 	"benchmarking-tool/reporter"
 	"benchmarking-tool/runner"
+	"benchmarking-tool/thresholds"
 )
 
-func run(args []string) error {
-	fmt.Println("Starting benchmarking tool...")
+// errThresholdsFailed marks a completed run that breached a configured threshold
+var errThresholdsFailed = errors.New("thresholds breached")
+
+// errRegressions marks a comparison that found regressions against the baseline
+var errRegressions = errors.New("performance regressions")
+
+// exitBenchmarkFailed is the exit code for a breached threshold or a
+// regression, so CI can tell a failing result (2) from a failure to run the
+// benchmark (1).
+const exitBenchmarkFailed = 2
+
+// cliOptions are the command-line settings that override the config file
+type cliOptions struct {
+	configFile string
+	jsonOut    string
+	samplesCSV string
+	summaryCSV string
+	htmlOut    string
+	junitOut   string
+	markdown   string
+	baseline   string
+	compare    compare.Options
+	progress   string
+	prometheus string
+	overrides  []config.Override // -set values in order, then the named load flags
+}
+
+// addCompareFlags registers the comparison tolerances shared by runs and the compare command
+func addCompareFlags(fs *flag.FlagSet, opts *compare.Options) {
+	*opts = compare.DefaultOptions()
+	fs.Float64Var(&opts.Tolerance, "tolerance", opts.Tolerance, "relative latency or throughput change accepted as noise")
+	fs.Float64Var(&opts.ErrorTolerance, "error-tolerance", opts.ErrorTolerance, "absolute error rate increase accepted as noise")
+	fs.Float64Var(&opts.Alpha, "alpha", opts.Alpha, "significance level for regressions")
+}
+
+// parseArgs reads "[flags] [config file] [flags]"; flags may follow the config path
+func parseArgs(args []string) (cliOptions, error) {
+	opts := cliOptions{configFile: "config.yaml"}
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.StringVar(&opts.jsonOut, "json", "", "write a JSON report to `path`")
+	fs.StringVar(&opts.samplesCSV, "samples-csv", "", "stream one CSV row per request to `path`")
+	fs.StringVar(&opts.summaryCSV, "summary-csv", "", "write aggregate results as CSV to `path`")
+	fs.StringVar(&opts.htmlOut, "html", "", "write an HTML report with charts to `path`")
+	fs.StringVar(&opts.junitOut, "junit", "", "write threshold checks as JUnit XML to `path`")
+	fs.StringVar(&opts.markdown, "markdown", "", "write a Markdown summary to `path` (e.g. $GITHUB_STEP_SUMMARY)")
+	fs.StringVar(&opts.baseline, "baseline", "", "compare the run with the JSON report at `path`")
+	fs.StringVar(&opts.prometheus, "prometheus", "", "serve live Prometheus metrics on `addr` (e.g. :9464)")
+	fs.StringVar(&opts.progress, "progress", "auto", "live progress: `mode` auto, live, log or off")
+	fs.Func("set", "set a config value, `path=value` with a dotted YAML path, e.g. execution.requestsPerSecond=500 (repeatable)", func(s string) error {
+		o, err := config.ParseOverride(s)
+		if err == nil {
+			opts.overrides = append(opts.overrides, o)
+		}
+		return err
+	})
+	var baseURLs []string
+	fs.Func("base-url", "target `url`, replacing baseUrls (repeatable)", func(s string) error {
+		baseURLs = append(baseURLs, s)
+		return nil
+	})
+	duration := fs.Duration("duration", 0, "test `duration` in whole seconds, e.g. 90s or 5m (execution.durationSeconds; not for ramp, staged concurrency or search mode)")
+	rps := fs.Int("rps", 0, "target requests per second (execution.requestsPerSecond)")
+	mode := fs.String("mode", "", "execution `mode`: fixed, ramp, concurrency, max or search")
+	workers := fs.Int("workers", 0, "worker pool size (execution.maxWorkers)")
+	addCompareFlags(fs, &opts.compare)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags] [config.yaml]\n       %s compare [flags] baseline.json current.json\n", args[0], args[0])
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return opts, err
+	}
+	if fs.NArg() > 0 {
+		opts.configFile = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return opts, err
+		}
+		if fs.NArg() > 0 {
+			return opts, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+		}
+	}
+	switch opts.progress {
+	case "auto", "live", "log", "off":
+	default:
+		return opts, fmt.Errorf("unknown -progress mode %q", opts.progress)
+	}
+
+	// The named flags win over -set, whatever their order on the command line
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "base-url":
+			opts.overrides = append(opts.overrides, config.Override{Path: "baseUrls", Value: baseURLs})
+		case "duration":
+			if *duration < time.Second || *duration%time.Second != 0 {
+				err = fmt.Errorf("-duration must be a whole number of seconds, got %s", *duration)
+			}
+			opts.overrides = append(opts.overrides, config.Override{Path: "execution.durationSeconds", Value: int(*duration / time.Second)})
+		case "rps":
+			opts.overrides = append(opts.overrides, config.Override{Path: "execution.requestsPerSecond", Value: *rps})
+		case "mode":
+			opts.overrides = append(opts.overrides, config.Override{Path: "execution.mode", Value: *mode})
+		case "workers":
+			opts.overrides = append(opts.overrides, config.Override{Path: "execution.maxWorkers", Value: *workers})
+		}
+	})
+	return opts, err
+}
+
+// runCompare is the compare command: it prints how current.json differs from baseline.json
+func runCompare(args []string) error {
+	var opts compare.Options
+	var markdown string
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	addCompareFlags(fs, &opts)
+	fs.StringVar(&markdown, "markdown", "", "also write the comparison as Markdown to `path`")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags] baseline.json current.json\n", args[0])
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("compare needs a baseline and a current report")
+	}
+	baseline, err := reporter.ReadJSONFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	current, err := reporter.ReadJSONFile(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	res := compare.Compare(baseline, current, opts)
+	compare.Write(os.Stdout, res)
+	if markdown != "" {
+		if err := writeMarkdownFile(markdown, nil, res); err != nil {
+			return err
+		}
+	}
+	if n := res.Regressions(); n > 0 {
+		return fmt.Errorf("%w: %d found", errRegressions, n)
+	}
+	return nil
+}
+
+// writeMarkdownFile appends the Markdown summary of rep followed by the
+// comparison with the baseline; either may be nil. Appending keeps what
+// earlier CI steps wrote to a shared file such as $GITHUB_STEP_SUMMARY.
+func writeMarkdownFile(path string, rep *reporter.Report, comparison *compare.Result) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open Markdown report: %w", err)
+	}
+	if info, statErr := f.Stat(); statErr == nil && info.Size() > 0 {
+		fmt.Fprintln(f) // Separate from the earlier content
+	}
+	if rep != nil {
+		err = reporter.WriteMarkdown(f, rep)
+	}
+	if err == nil && comparison != nil {
+		if rep != nil {
+			fmt.Fprintln(f)
+		}
+		err = compare.WriteMarkdown(f, comparison)
+	}
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write Markdown report: %w", err)
+	}
+	return f.Close()
+}
 
-	configFile := "config.yaml"
-	if len(args) > 1 {
-		configFile = args[1]
+func run(args []string) error {
+	if len(args) > 1 && args[1] == "compare" {
+		return runCompare(args[1:])
+	}
+	opts, err := parseArgs(args)
+	if err != nil {
+		return err
 	}
 
-	cfg, err := config.LoadConfig(configFile)
+	fmt.Println("Starting benchmarking tool...")
+
+	cfg, err := config.LoadConfig(opts.configFile, opts.overrides...)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
+	if opts.jsonOut != "" {
+		cfg.Output.JSON = opts.jsonOut
+	}
+	if opts.samplesCSV != "" {
+		cfg.Output.SamplesCSV = opts.samplesCSV
+	}
+	if opts.summaryCSV != "" {
+		cfg.Output.SummaryCSV = opts.summaryCSV
+	}
+	if opts.htmlOut != "" {
+		cfg.Output.HTML = opts.htmlOut
+	}
+	if opts.junitOut != "" {
+		cfg.Output.JUnit = opts.junitOut
+	}
+	if opts.markdown != "" {
+		cfg.Output.Markdown = opts.markdown
+	}
+	if opts.prometheus != "" {
+		cfg.Metrics.Prometheus = opts.prometheus
+	}
+
+	var reportTemplate *reporter.Template
+	if path := cfg.Report.TemplatePath(); path != "" {
+		// Parsed up front so a template error fails before the benchmark runs
+		if reportTemplate, err = reporter.ParseTemplateFile(path); err != nil {
+			return err
+		}
+	}
+
+	var baseline *reporter.Report
+	if opts.baseline != "" {
+		// Read up front so a bad path fails before the benchmark runs
+		if baseline, err = reporter.ReadJSONFile(opts.baseline); err != nil {
+			return err
+		}
+	}
 
 	fmt.Printf("Configuration loaded: Mode='%s', Duration=%ds, RPS=%d\n",
 		cfg.Execution.Mode, cfg.Execution.DurationSeconds, cfg.Execution.RequestsPerSecond)
@@ -33,26 +257,166 @@ func run(args []string) error {
 	}
 	fmt.Printf("Parameter generators defined: %d\n", len(cfg.ParameterGenerators))
 
-	metricsCollector := metrics.NewCollector()
+	metricsCollector := metrics.NewCollectorWithOptions(metrics.Options{
+		HistogramPrecision: cfg.Metrics.HistogramPrecision,
+		Percentiles:        cfg.Metrics.Percentiles,
+		SampleSize:         cfg.Metrics.SampleSize,
+		Window:             time.Duration(cfg.Metrics.WindowSeconds) * time.Second,
+	})
 	benchmarkRunner := runner.NewRunner(cfg, metricsCollector)
+	if cfg.Metrics.Prometheus != "" {
+		exporter := prometheus.NewExporter(benchmarkRunner.Progress)
+		server, err := prometheus.Listen(cfg.Metrics.Prometheus, exporter)
+		if err != nil {
+			return err
+		}
+		defer server.Close()
+		metricsCollector.AddSink(exporter)
+		fmt.Printf("Serving Prometheus metrics on http://%s/metrics\n", server.Addr())
+	}
+	var spanExporter *otlp.Exporter
+	if cfg.Tracing.OTLPEndpoint != "" {
+		spanExporter = otlp.NewExporter(cfg.Tracing)
+		metricsCollector.AddSink(spanExporter)
+		spanExporter.Start()
+	}
+	var pusher *metrics.Pusher
+	if cfg.Metrics.Influx.Enabled() {
+		writer, err := influx.NewWriter(cfg.Metrics.Influx)
+		if err != nil {
+			return err
+		}
+		defer writer.Close()
+		pusher = metrics.NewPusher(writer, metrics.PushOptions{
+			Window:             time.Duration(cfg.Metrics.WindowSeconds) * time.Second,
+			Interval:           time.Duration(cfg.Metrics.Influx.IntervalSeconds) * time.Second,
+			Percentiles:        cfg.Metrics.Percentiles,
+			HistogramPrecision: cfg.Metrics.HistogramPrecision,
+		})
+		metricsCollector.AddSink(pusher)
+		pusher.Start()
+	}
+	var samplesCSV *reporter.SampleCSVWriter
+	if cfg.Output.SamplesCSV != "" {
+		if samplesCSV, err = reporter.NewSampleCSVWriter(cfg.Output.SamplesCSV); err != nil {
+			return err
+		}
+		metricsCollector.AddSink(samplesCSV)
+	}
+	var live *reporter.LiveView
+	if opts.progress != "off" {
+		tty := opts.progress == "live" || (opts.progress == "auto" && reporter.IsTerminal(os.Stdout))
+		out := os.Stdout
+		if !tty {
+			out = os.Stderr // Alongside the runner's log lines
+		}
+		live = reporter.NewLiveView(out, tty, time.Duration(cfg.Execution.DurationSeconds)*time.Second, benchmarkRunner.Progress)
+		metricsCollector.AddSink(live)
+		live.Start()
+	}
 
-	_, err = benchmarkRunner.Run()
+	startedAt := time.Now()
+	runResult, err := benchmarkRunner.Run()
+	if live != nil {
+		live.Stop()
+	}
+	if spanExporter != nil {
+		if exportErr := spanExporter.Stop(); exportErr != nil {
+			log.Printf("Warning: span export: %v", exportErr)
+		}
+	}
+	if pusher != nil {
+		// A failed push loses points downstream but does not invalidate the run
+		if pushErr := pusher.Stop(); pushErr != nil {
+			log.Printf("Warning: InfluxDB push: %v", pushErr)
+		}
+	}
+	if samplesCSV != nil {
+		// Close even when the run failed so the rows recorded so far are kept
+		if closeErr := samplesCSV.Close(); closeErr != nil && err == nil {
+			return closeErr
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("error during benchmark execution: %w", err)
 	}
+	info := reporter.RunInfo{ConfigFile: opts.configFile, StartedAt: startedAt, FinishedAt: time.Now()}
 
 	finalResults := metricsCollector.GetResults()
 
-	// Generate report using the proper Reporter
-	rep := reporter.NewReporter()
-	rep.Generate(cfg, finalResults)
+	report := reporter.NewReport(cfg, finalResults, runResult, info)
+	switch {
+	case reportTemplate == nil:
+		reporter.NewReporter().GenerateRun(cfg, finalResults, runResult)
+	case cfg.Report.Output == "":
+		if err := reportTemplate.Execute(os.Stdout, report); err != nil {
+			return err
+		}
+	default:
+		reporter.NewReporter().GenerateRun(cfg, finalResults, runResult)
+		if err := reportTemplate.ExecuteFile(cfg.Report.Output, report); err != nil {
+			return err
+		}
+		fmt.Printf("Templated report written to %s\n", cfg.Report.Output)
+	}
+	if cfg.Output.JSON != "" {
+		if err := reporter.WriteJSONFile(cfg.Output.JSON, report); err != nil {
+			return err
+		}
+		fmt.Printf("JSON report written to %s\n", cfg.Output.JSON)
+	}
+	if cfg.Output.HTML != "" {
+		if err := reporter.WriteHTMLFile(cfg.Output.HTML, report); err != nil {
+			return err
+		}
+		fmt.Printf("HTML report written to %s\n", cfg.Output.HTML)
+	}
+	if cfg.Output.JUnit != "" {
+		if err := reporter.WriteJUnitFile(cfg.Output.JUnit, report); err != nil {
+			return err
+		}
+		fmt.Printf("JUnit report written to %s\n", cfg.Output.JUnit)
+	}
+	if cfg.Output.SummaryCSV != "" {
+		if err := reporter.WriteSummaryCSV(cfg.Output.SummaryCSV, finalResults); err != nil {
+			return err
+		}
+		fmt.Printf("Summary CSV written to %s\n", cfg.Output.SummaryCSV)
+	}
+	if samplesCSV != nil {
+		fmt.Printf("Samples CSV written to %s\n", cfg.Output.SamplesCSV)
+	}
+
+	var comparison *compare.Result
+	if baseline != nil {
+		fmt.Printf("\n--- Comparison with %s ---\n", opts.baseline)
+		comparison = compare.Compare(baseline, report, opts.compare)
+		compare.Write(os.Stdout, comparison)
+	}
+	if cfg.Output.Markdown != "" {
+		if err := writeMarkdownFile(cfg.Output.Markdown, report, comparison); err != nil {
+			return err
+		}
+		fmt.Printf("Markdown summary written to %s\n", cfg.Output.Markdown)
+	}
 
 	fmt.Println("Benchmarking tool finished.")
-	return nil
+	var errs []error
+	if failed := thresholds.Failed(report.Thresholds); failed > 0 {
+		errs = append(errs, fmt.Errorf("%w: %d of %d checks failed", errThresholdsFailed, failed, len(report.Thresholds)))
+	}
+	if comparison != nil && comparison.Regressions() > 0 {
+		errs = append(errs, fmt.Errorf("%w: %d found", errRegressions, comparison.Regressions()))
+	}
+	return errors.Join(errs...)
 }
 
 func main() {
 	if err := run(os.Args); err != nil {
+		if errors.Is(err, errThresholdsFailed) || errors.Is(err, errRegressions) {
+			log.Printf("Benchmark failed: %v", err)
+			os.Exit(exitBenchmarkFailed)
+		}
 		log.Fatalf("Application error: %v", err)
 	}
 }
@@ -1,9 +1,17 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"benchmarking-tool/reporter"
 )
 
 func TestRun_MissingConfigFile(t *testing.T) {
@@ -16,3 +24,265 @@ func TestRun_MissingConfigFile(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestParseArgs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		args       []string
+		wantConfig string
+		wantJSON   string
+		wantErr    bool
+	}{
+		{"defaults", []string{"bt"}, "config.yaml", "", false},
+		{"config only", []string{"bt", "bench.yml"}, "bench.yml", "", false},
+		{"flag before config", []string{"bt", "-json", "out.json", "bench.yml"}, "bench.yml", "out.json", false},
+		{"flag after config", []string{"bt", "bench.yml", "--json=out.json"}, "bench.yml", "out.json", false},
+		{"extra argument", []string{"bt", "a.yml", "b.yml"}, "", "", true},
+		{"unknown flag", []string{"bt", "-nope"}, "", "", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseArgs(tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if opts.configFile != tc.wantConfig || opts.jsonOut != tc.wantJSON {
+				t.Fatalf("got config %q json %q, want %q %q", opts.configFile, opts.jsonOut, tc.wantConfig, tc.wantJSON)
+			}
+		})
+	}
+}
+
+func TestParseArgs_Overrides(t *testing.T) {
+	opts, err := parseArgs([]string{"bt", "-rps", "200", "bench.yml", "--set", "execution.requestsPerSecond=500",
+		"-base-url", "http://a", "-base-url", "http://b", "-duration", "2m", "--set", "metrics.percentiles=[50, 99]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(opts.overrides))
+	for _, o := range opts.overrides {
+		got = append(got, fmt.Sprintf("%s=%v", o.Path, o.Value))
+	}
+	want := []string{
+		"execution.requestsPerSecond=500",
+		"metrics.percentiles=[50 99]",
+		"baseUrls=[http://a http://b]",
+		"execution.durationSeconds=120",
+		"execution.requestsPerSecond=200",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("got overrides %q, want %q", got, want)
+	}
+
+	for _, args := range [][]string{{"bt", "-duration", "1500ms"}, {"bt", "-set", "execution.mode"}} {
+		if _, err := parseArgs(args); err == nil {
+			t.Errorf("expected an error for %v", args)
+		}
+	}
+}
+
+func TestRun_WritesReportFiles(t *testing.T) {
+	var traced atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("traceparent") != "" {
+			traced.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	var exports atomic.Int64
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exports.Add(1)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer collector.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	influxPath := filepath.Join(dir, "points.lp")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: fixed
+  durationSeconds: 1
+  requestsPerSecond: 5
+  requestTimeoutMs: 2000
+endpoints:
+  root:
+    path: "/"
+    method: GET
+endpointSelection:
+  strategy: roundRobin
+thresholds:
+  maxErrorRate: 0
+  endpoints:
+    root:
+      percentiles:
+        p99: 2000
+metrics:
+  influx:
+    file: "` + influxPath + `"
+tracing:
+  otlpEndpoint: "` + collector.URL + `/v1/traces"
+report:
+  template: "report.tmpl"
+  output: "` + filepath.Join(dir, "report.txt") + `"
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.tmpl"), []byte("root={{(index .Endpoints \"root\").Requests}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	jsonPath := filepath.Join(dir, "report.json")
+	samplesPath := filepath.Join(dir, "samples.csv")
+	summaryPath := filepath.Join(dir, "summary.csv")
+	htmlPath := filepath.Join(dir, "report.html")
+	junitPath := filepath.Join(dir, "junit.xml")
+	args := []string{"benchmarking-tool", cfgPath, "-json", jsonPath, "-samples-csv", samplesPath, "-summary-csv", summaryPath,
+		"-html", htmlPath, "-junit", junitPath}
+	if err := run(args); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := reporter.ReadJSONFile(jsonPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Metadata.ConfigFile != cfgPath || rep.Config.Mode != "fixed" {
+		t.Fatalf("unexpected metadata %+v / config %+v", rep.Metadata, rep.Config)
+	}
+	if rep.Results.Requests < 1 || rep.Endpoints["root"].Requests != rep.Results.Requests {
+		t.Fatalf("expected requests attributed to root, got %d of %d", rep.Endpoints["root"].Requests, rep.Results.Requests)
+	}
+
+	if traced.Load() != rep.Results.Requests || exports.Load() == 0 || rep.Traces == nil || len(rep.Traces.Slowest) == 0 {
+		t.Fatalf("expected traced requests exported to the collector, got %d traced, %d exports, %+v", traced.Load(), exports.Load(), rep.Traces)
+	}
+
+	if len(rep.Thresholds) != 2 || !rep.Thresholds[0].Passed || !rep.Thresholds[1].Passed {
+		t.Fatalf("expected two passing threshold checks, got %+v", rep.Thresholds)
+	}
+
+	samples, err := os.ReadFile(samplesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows := strings.Count(string(samples), "\n"); int64(rows) != rep.Results.Requests+1 {
+		t.Fatalf("expected a header and %d sample rows, got %d lines", rep.Results.Requests, rows)
+	}
+	for _, path := range []string{summaryPath, htmlPath, junitPath} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("report not written: %v", err)
+		}
+	}
+	points, err := os.ReadFile(influxPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(points), "benchmark,scope=endpoint,endpoint=root requests=") {
+		t.Fatalf("expected line protocol points for root, got:\n%s", points)
+	}
+	if templated, err := os.ReadFile(filepath.Join(dir, "report.txt")); err != nil || string(templated) != fmt.Sprintf("root=%d", rep.Results.Requests) {
+		t.Fatalf("expected the templated report, got %q (%v)", templated, err)
+	}
+
+	// A second run compared with the first; loose tolerances keep timing noise out
+	markdownPath := filepath.Join(dir, "summary.md")
+	if err := run([]string{"benchmarking-tool", cfgPath, "-baseline", jsonPath, "-tolerance", "100", "-error-tolerance", "1", "-markdown", markdownPath}); err != nil {
+		t.Fatalf("comparison with the baseline failed: %v", err)
+	}
+	markdown, err := os.ReadFile(markdownPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(markdown), "## Benchmark results") || !strings.Contains(string(markdown), "### Comparison with baseline") {
+		t.Fatalf("expected the run summary and the comparison, got:\n%s", markdown)
+	}
+	if err := run([]string{"benchmarking-tool", cfgPath, "-baseline", filepath.Join(dir, "missing.json")}); err == nil {
+		t.Fatal("expected an error for a missing baseline")
+	}
+}
+
+func TestRun_ThresholdBreach(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: fixed
+  durationSeconds: 1
+  requestsPerSecond: 5
+endpoints:
+  root:
+    path: "/"
+    method: GET
+thresholds:
+  maxErrorRate: 0.5
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	junitPath := filepath.Join(dir, "junit.xml")
+	err := run([]string{"benchmarking-tool", cfgPath, "-junit", junitPath})
+	if !errors.Is(err, errThresholdsFailed) {
+		t.Fatalf("expected a threshold failure, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "1 of 1 checks failed") {
+		t.Fatalf("unexpected error message: %v", err)
+	}
+	if _, err := os.Stat(junitPath); err != nil {
+		t.Fatalf("reports should be written before failing: %v", err)
+	}
+	if err := run([]string{"benchmarking-tool", cfgPath, "--set", "thresholds.maxErrorRate=1"}); err != nil {
+		t.Fatalf("a relaxed threshold from the command line should pass, got %v", err)
+	}
+}
+
+func TestRunCompare(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, p99 float64) string {
+		rep := &reporter.Report{
+			Config:  reporter.ConfigSummary{Percentiles: []float64{99}},
+			Results: reporter.ResultSummary{Requests: 100, AchievedRPS: 10, Latency: reporter.LatencySummary{Percentiles: map[string]float64{"p99": p99}}},
+		}
+		path := filepath.Join(dir, name)
+		if err := reporter.WriteJSONFile(path, rep); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	base, same, slow := write("base.json", 100), write("same.json", 102), write("slow.json", 150)
+
+	if err := run([]string{"benchmarking-tool", "compare", base, same}); err != nil {
+		t.Fatalf("a 2%% change should pass, got %v", err)
+	}
+	if err := run([]string{"benchmarking-tool", "compare", "-tolerance", "0.6", base, slow}); err != nil {
+		t.Fatalf("a 50%% change should pass a 60%% tolerance, got %v", err)
+	}
+	// Appended like a CI step summary, keeping what earlier steps wrote
+	markdownPath := filepath.Join(dir, "comparison.md")
+	if err := os.WriteFile(markdownPath, []byte("## Unit tests\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"benchmarking-tool", "compare", "-markdown", markdownPath, base, slow}); !errors.Is(err, errRegressions) {
+		t.Fatalf("expected a regression, got %v", err)
+	}
+	if markdown, err := os.ReadFile(markdownPath); err != nil || !strings.HasPrefix(string(markdown), "## Unit tests\n\n### Comparison with baseline") {
+		t.Fatalf("expected the comparison appended as Markdown, got %q (%v)", markdown, err)
+	}
+	if err := run([]string{"benchmarking-tool", "compare", base}); err == nil {
+		t.Fatal("expected an error for a missing report argument")
+	}
+}
@@ -0,0 +1,189 @@
+package metrics
+
+import "time"
+
+// maxErrorKinds bounds the distinct error messages tracked per aggregate;
+// further messages are counted under OtherErrorsKey so that errors embedding
+// unique data (ports, request IDs) cannot grow memory without limit.
+const maxErrorKinds = 1000
+
+// OtherErrorsKey collects error messages beyond the first maxErrorKinds distinct ones.
+const OtherErrorsKey = "(other errors)"
+
+// aggregator folds MetricDetails into running totals and histograms, so its
+// size depends on the spread of values rather than the number of requests.
+type aggregator struct {
+	total, successful, failed int64
+	totalDuration             time.Duration
+	minDuration, maxDuration  time.Duration
+	totalResponse             time.Duration
+	maxResponse               time.Duration
+	missed                    int64 // Dropped slots counted only in the response times
+	bytesSent, bytesReceived  int64
+	statusCodes               map[int]int64
+	errors                    map[string]int
+	latency                   *Histogram
+	responseLatency           *Histogram
+	windowStart, windowEnd    time.Time
+}
+
+func newAggregator(precision int) *aggregator {
+	return &aggregator{
+		statusCodes:     make(map[int]int64),
+		errors:          make(map[string]int),
+		latency:         NewHistogram(precision),
+		responseLatency: NewHistogram(precision),
+	}
+}
+
+// newLatencyAggregator is an aggregator without the response-time
+// histogram, for the time series where only service time is charted
+func newLatencyAggregator(precision int) *aggregator {
+	return &aggregator{
+		statusCodes: make(map[int]int64),
+		errors:      make(map[string]int),
+		latency:     NewHistogram(precision),
+	}
+}
+
+// add folds one completed request into the aggregate
+func (a *aggregator) add(r MetricDetail) {
+	start := r.Timestamp.Add(-r.Duration)
+	if a.total == 0 {
+		a.minDuration = r.Duration // Initialize with the first request
+		a.windowStart, a.windowEnd = start, r.Timestamp
+	}
+	if start.Before(a.windowStart) {
+		a.windowStart = start
+	}
+	if r.Timestamp.After(a.windowEnd) {
+		a.windowEnd = r.Timestamp
+	}
+	a.total++
+	a.totalDuration += r.Duration
+	a.minDuration = min(a.minDuration, r.Duration)
+	a.maxDuration = max(a.maxDuration, r.Duration)
+	rt := r.responseTime()
+	a.totalResponse += rt
+	a.maxResponse = max(a.maxResponse, rt)
+	a.bytesSent += r.BytesSent
+	a.bytesReceived += r.BytesReceived
+	a.latency.Record(r.Duration)
+	if a.responseLatency != nil {
+		a.responseLatency.Record(rt)
+	}
+
+	if r.IsError || r.StatusCode >= 400 {
+		a.failed++
+		if r.ErrorMsg != "" {
+			a.addErrors(r.ErrorMsg, 1)
+		}
+	} else {
+		a.successful++
+	}
+	a.statusCodes[r.StatusCode]++
+}
+
+// addErrors counts n occurrences of msg, under OtherErrorsKey once
+// maxErrorKinds distinct messages are tracked
+func (a *aggregator) addErrors(msg string, n int) {
+	if _, seen := a.errors[msg]; seen || len(a.errors) < maxErrorKinds {
+		a.errors[msg] += n
+	} else {
+		a.errors[OtherErrorsKey] += n
+	}
+}
+
+// addMissed folds the corrected response time of a slot that was never sent
+// into the response-time distribution only
+func (a *aggregator) addMissed(rt time.Duration) {
+	a.missed++
+	a.totalResponse += rt
+	a.maxResponse = max(a.maxResponse, rt)
+	if a.responseLatency != nil {
+		a.responseLatency.Record(rt)
+	}
+}
+
+// merge folds everything b recorded into a
+func (a *aggregator) merge(b *aggregator) {
+	if b.total > 0 {
+		if a.total == 0 {
+			a.minDuration = b.minDuration
+			a.windowStart, a.windowEnd = b.windowStart, b.windowEnd
+		}
+		a.minDuration = min(a.minDuration, b.minDuration)
+		if b.windowStart.Before(a.windowStart) {
+			a.windowStart = b.windowStart
+		}
+		if b.windowEnd.After(a.windowEnd) {
+			a.windowEnd = b.windowEnd
+		}
+	}
+	a.total += b.total
+	a.successful += b.successful
+	a.failed += b.failed
+	a.totalDuration += b.totalDuration
+	a.maxDuration = max(a.maxDuration, b.maxDuration)
+	a.totalResponse += b.totalResponse
+	a.maxResponse = max(a.maxResponse, b.maxResponse)
+	a.missed += b.missed
+	a.bytesSent += b.bytesSent
+	a.bytesReceived += b.bytesReceived
+	for code, n := range b.statusCodes {
+		a.statusCodes[code] += n
+	}
+	for msg, n := range b.errors {
+		a.addErrors(msg, n)
+	}
+	a.latency.Merge(b.latency)
+	if a.responseLatency != nil {
+		a.responseLatency.Merge(b.responseLatency)
+	}
+}
+
+// results snapshots the aggregate; maps and histograms are copied so the
+// caller may keep them while recording continues.
+func (a *aggregator) results(percentiles []float64) AggregatedResults {
+	res := AggregatedResults{
+		TotalRequests:      a.total,
+		SuccessfulRequests: a.successful,
+		FailedRequests:     a.failed,
+		TotalDuration:      a.totalDuration,
+		MinDuration:        a.minDuration,
+		MaxDuration:        a.maxDuration,
+		TotalResponseTime:  a.totalResponse,
+		MaxResponseTime:    a.maxResponse,
+		BytesSent:          a.bytesSent,
+		BytesReceived:      a.bytesReceived,
+		StatusCodesCount:   make(map[int]int64, len(a.statusCodes)),
+		ErrorDetails:       make(map[string]int, len(a.errors)),
+		Latency:            a.latency.Clone(),
+	}
+	for code, n := range a.statusCodes {
+		res.StatusCodesCount[code] = n
+	}
+	for msg, n := range a.errors {
+		res.ErrorDetails[msg] = n
+	}
+	if n := a.total + a.missed; n > 0 {
+		res.AvgResponseTime = a.totalResponse / time.Duration(n)
+	}
+	if a.total > 0 {
+		res.AvgDuration = a.totalDuration / time.Duration(a.total)
+		res.AvgBytesSent = a.bytesSent / a.total
+		res.AvgBytesReceived = a.bytesReceived / a.total
+	}
+	res.Percentiles = res.Latency.Percentiles(percentiles)
+	if a.responseLatency != nil {
+		res.ResponseLatency = a.responseLatency.Clone()
+		res.ResponsePercentiles = res.ResponseLatency.Percentiles(percentiles)
+	}
+	res.Elapsed = a.windowEnd.Sub(a.windowStart)
+	if res.Elapsed > 0 {
+		res.AchievedRPS = float64(res.TotalRequests) / res.Elapsed.Seconds()
+		res.SentPerSecond = float64(res.BytesSent) / res.Elapsed.Seconds()
+		res.ReceivedPerSecond = float64(res.BytesReceived) / res.Elapsed.Seconds()
+	}
+	return res
+}
@@ -0,0 +1,216 @@
+package metrics
+
+import (
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultHistogramPrecision is the number of significant decimal digits kept by latency histograms.
+	DefaultHistogramPrecision = 3
+	// MinHistogramPrecision and MaxHistogramPrecision bound the configurable precision.
+	MinHistogramPrecision = 1
+	MaxHistogramPrecision = 5
+)
+
+// DefaultPercentiles are reported when none are configured.
+var DefaultPercentiles = []float64{50, 90, 95, 99}
+
+// PercentileValue is one latency percentile (Percentile is 0-100).
+type PercentileValue struct {
+	Percentile float64
+	Value      time.Duration
+}
+
+// PercentileLabel formats a percentile for display ("p50", "p99.9").
+func PercentileLabel(p float64) string {
+	return "p" + strconv.FormatFloat(p, 'f', -1, 64)
+}
+
+// Histogram records durations in HDR-style log-linear buckets: every value is
+// kept to the configured number of significant digits regardless of
+// magnitude, so memory depends on the spread of values rather than the count.
+// Buckets are stored sparsely, which keeps small per-window histograms cheap.
+// A Histogram is not safe for concurrent use.
+type Histogram struct {
+	precision     int
+	subBucketBits uint
+	counts        map[int32]int64
+	total         int64
+	sum           time.Duration
+	min           time.Duration
+	max           time.Duration
+}
+
+// NewHistogram creates a histogram keeping precision significant decimal
+// digits (clamped to 1-5).
+func NewHistogram(precision int) *Histogram {
+	precision = max(MinHistogramPrecision, min(precision, MaxHistogramPrecision))
+	// Enough linear sub-buckets per power of two to resolve 1 part in 10^precision.
+	largest := 2 * math.Pow10(precision)
+	return &Histogram{
+		precision:     precision,
+		subBucketBits: uint(math.Ceil(math.Log2(largest))),
+		counts:        make(map[int32]int64),
+	}
+}
+
+// Precision returns the number of significant decimal digits kept.
+func (h *Histogram) Precision() int {
+	return h.precision
+}
+
+// bucketIndex maps a non-negative value to its bucket. Values below
+// 2^subBucketBits get one bucket each; above that every power of two is split
+// into 2^(subBucketBits-1) equal buckets.
+func (h *Histogram) bucketIndex(v int64) int32 {
+	subCount := int64(1) << h.subBucketBits
+	if v < subCount {
+		return int32(v)
+	}
+	shift := uint(bits.Len64(uint64(v))) - h.subBucketBits
+	half := subCount / 2
+	sub := v >> shift // in [half, subCount)
+	return int32(subCount + int64(shift-1)*half + (sub - half))
+}
+
+// bucketRange returns the lowest and highest value that map to index.
+func (h *Histogram) bucketRange(index int32) (low, high int64) {
+	subCount := int64(1) << h.subBucketBits
+	if int64(index) < subCount {
+		return int64(index), int64(index)
+	}
+	half := subCount / 2
+	rel := int64(index) - subCount
+	shift := uint(rel/half) + 1
+	sub := half + rel%half
+	return sub << shift, (sub+1)<<shift - 1
+}
+
+// Record adds one observation; negative durations are recorded as 0.
+func (h *Histogram) Record(d time.Duration) {
+	h.RecordN(d, 1)
+}
+
+// RecordN adds n observations of d.
+func (h *Histogram) RecordN(d time.Duration, n int64) {
+	if n <= 0 {
+		return
+	}
+	d = max(d, 0)
+	h.counts[h.bucketIndex(int64(d))] += n
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total += n
+	h.sum += d * time.Duration(n)
+}
+
+// Merge adds every observation of other into h. Histograms of different
+// precision are merged at h's precision.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.total == 0 {
+		return
+	}
+	if other.precision == h.precision {
+		for idx, n := range other.counts {
+			h.counts[idx] += n
+		}
+	} else {
+		for idx, n := range other.counts {
+			low, high := other.bucketRange(idx)
+			h.counts[h.bucketIndex(low+(high-low)/2)] += n
+		}
+	}
+	if h.total == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	h.max = max(h.max, other.max)
+	h.total += other.total
+	h.sum += other.sum
+}
+
+// Clone returns an independent copy of h.
+func (h *Histogram) Clone() *Histogram {
+	c := *h
+	c.counts = make(map[int32]int64, len(h.counts))
+	for idx, n := range h.counts {
+		c.counts[idx] = n
+	}
+	return &c
+}
+
+// Count returns the number of recorded observations.
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Min returns the smallest recorded value (exact).
+func (h *Histogram) Min() time.Duration {
+	return h.min
+}
+
+// Max returns the largest recorded value (exact).
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Mean returns the exact mean of the recorded values.
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+// ValueAtPercentile returns the value below which p percent (0-100) of the
+// observations fall, to the histogram's precision. Results are clamped to the
+// exact recorded min and max.
+func (h *Histogram) ValueAtPercentile(p float64) time.Duration {
+	return h.Percentiles([]float64{p})[0].Value
+}
+
+// Percentiles returns ValueAtPercentile for each p, in the given order, in a
+// single pass over the buckets.
+func (h *Histogram) Percentiles(ps []float64) []PercentileValue {
+	out := make([]PercentileValue, len(ps))
+	for i, p := range ps {
+		out[i].Percentile = p
+	}
+	if h.total == 0 {
+		return out
+	}
+
+	order := make([]int, len(ps))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return ps[order[a]] < ps[order[b]] })
+
+	indexes := make([]int32, 0, len(h.counts))
+	for idx := range h.counts {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	var seen int64
+	next := 0
+	for _, i := range order {
+		p := max(0, min(ps[i], 100))
+		rank := int64(math.Ceil(p * float64(h.total) / 100))
+		rank = max(1, min(rank, h.total))
+		for seen < rank {
+			seen += h.counts[indexes[next]]
+			next++
+		}
+		_, high := h.bucketRange(indexes[next-1])
+		out[i].Value = max(h.min, min(time.Duration(high), h.max))
+	}
+	return out
+}
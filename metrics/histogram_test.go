@@ -0,0 +1,129 @@
+package metrics
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestHistogram_BucketRoundTrip(t *testing.T) {
+	for _, precision := range []int{1, 3, 5} {
+		h := NewHistogram(precision)
+		for _, v := range []int64{0, 1, 2047, 2048, 4095, 4096, 123456789, int64(time.Hour)} {
+			low, high := h.bucketRange(h.bucketIndex(v))
+			if v < low || v > high {
+				t.Errorf("precision %d: value %d outside its bucket [%d, %d]", precision, v, low, high)
+			}
+		}
+	}
+}
+
+func TestHistogram_PrecisionBound(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, precision := range []int{2, 3, 4} {
+		h := NewHistogram(precision)
+		values := make([]time.Duration, 10_000)
+		for i := range values {
+			// Spread over five orders of magnitude
+			values[i] = time.Duration(rng.Float64() * rng.Float64() * float64(10*time.Second))
+			h.Record(values[i])
+		}
+		sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+		tolerance := math.Pow10(-precision)
+		for _, p := range []float64{1, 25, 50, 75, 90, 99, 99.9} {
+			rank := int(math.Ceil(p*float64(len(values))/100)) - 1
+			want := values[max(0, rank)]
+			got := h.ValueAtPercentile(p)
+			if diff := float64(got-want) / float64(want); diff < -tolerance || diff > tolerance {
+				t.Errorf("precision %d %s = %v, exact %v (error %.5f > %.5f)", precision, PercentileLabel(p), got, want, diff, tolerance)
+			}
+		}
+	}
+}
+
+func TestHistogram_MinMaxMean(t *testing.T) {
+	h := NewHistogram(DefaultHistogramPrecision)
+	if h.ValueAtPercentile(50) != 0 || h.Mean() != 0 {
+		t.Fatal("empty histogram should report zeros")
+	}
+	h.Record(10 * time.Millisecond)
+	h.RecordN(30*time.Millisecond, 3)
+	h.Record(-time.Second) // clamped to 0
+
+	if h.Count() != 5 {
+		t.Errorf("Count = %d, want 5", h.Count())
+	}
+	if h.Min() != 0 || h.Max() != 30*time.Millisecond {
+		t.Errorf("Min/Max = %v/%v, want 0/30ms", h.Min(), h.Max())
+	}
+	if h.Mean() != 20*time.Millisecond {
+		t.Errorf("Mean = %v, want 20ms", h.Mean())
+	}
+	if got := h.ValueAtPercentile(100); got != 30*time.Millisecond {
+		t.Errorf("p100 = %v, want exact max 30ms", got)
+	}
+	if got := h.ValueAtPercentile(0); got != 0 {
+		t.Errorf("p0 = %v, want exact min 0", got)
+	}
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a := NewHistogram(3)
+	b := NewHistogram(3)
+	all := NewHistogram(3)
+	for i := 1; i <= 500; i++ {
+		a.Record(time.Duration(i) * time.Millisecond)
+		all.Record(time.Duration(i) * time.Millisecond)
+	}
+	for i := 501; i <= 1000; i++ {
+		b.Record(time.Duration(i) * time.Millisecond)
+		all.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	merged := a.Clone()
+	merged.Merge(b)
+	if merged.Count() != 1000 || a.Count() != 500 {
+		t.Fatalf("merged count %d (source %d), want 1000 (500)", merged.Count(), a.Count())
+	}
+	for _, p := range DefaultPercentiles {
+		if merged.ValueAtPercentile(p) != all.ValueAtPercentile(p) {
+			t.Errorf("%s: merged %v, direct %v", PercentileLabel(p), merged.ValueAtPercentile(p), all.ValueAtPercentile(p))
+		}
+	}
+
+	// Merging a coarser histogram re-buckets at the receiver's precision
+	coarse := NewHistogram(1)
+	coarse.Record(time.Second)
+	fine := NewHistogram(4)
+	fine.Merge(coarse)
+	if got := fine.ValueAtPercentile(50); got != time.Second {
+		t.Errorf("cross-precision p50 = %v, want 1s", got)
+	}
+}
+
+func TestHistogram_PercentilesOrder(t *testing.T) {
+	h := NewHistogram(3)
+	for i := 1; i <= 100; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	got := h.Percentiles([]float64{99, 50, 90})
+	for i, want := range []float64{99, 50, 90} {
+		if got[i].Percentile != want {
+			t.Fatalf("Percentiles[%d] = %s, want p%v", i, PercentileLabel(got[i].Percentile), want)
+		}
+		if got[i].Value != h.ValueAtPercentile(want) {
+			t.Errorf("Percentiles[%d] = %v, want %v", i, got[i].Value, h.ValueAtPercentile(want))
+		}
+	}
+}
+
+func TestPercentileLabel(t *testing.T) {
+	for p, want := range map[float64]string{50: "p50", 99.9: "p99.9", 99.99: "p99.99"} {
+		if got := PercentileLabel(p); got != want {
+			t.Errorf("PercentileLabel(%v) = %q, want %q", p, got, want)
+		}
+	}
+}
@@ -10,46 +10,213 @@ type MetricDetail struct {
 	URL        string
 	Method     string
 	StatusCode int
-	Duration   time.Duration
+	Duration   time.Duration // Service time: from when a worker started the request to completion
 	IsError    bool
 	ErrorMsg   string
 	Timestamp  time.Time
+	Stage      string // Load stage the request was scheduled in ("" outside staged modes)
+	Endpoint   string // Configured endpoint name ("" when recorded without one)
+	BaseURL    string // Base URL the request was sent to
+	// IntendedStart is when the scheduler meant the request to start (zero in closed-loop modes).
+	IntendedStart time.Time
+	// ResponseTime is the coordinated-omission-corrected latency, IntendedStart to
+	// completion, so time spent queued inside the tool is not hidden. Equals Duration
+	// when there is no intended start; 0 is treated as Duration.
+	ResponseTime time.Duration
+	Timings      RequestTimings // HTTP phase breakdown of Duration (zero when the request was never sent)
+	// BytesSent and BytesReceived are the request and response body sizes
+	BytesSent     int64
+	BytesReceived int64
+	// TraceID and SpanID are the W3C trace context of the request, hex encoded
+	// (empty when the request was never sent)
+	TraceID string
+	SpanID  string
 }
 
-// Collector stores benchmark metrics
+// stageSpan records the wall-clock window of a named load stage
+type stageSpan struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+// Options tunes how a Collector summarises latencies.
+type Options struct {
+	HistogramPrecision int           // Significant digits kept by latency histograms (0 = DefaultHistogramPrecision)
+	Percentiles        []float64     // Percentiles reported in AggregatedResults (nil = DefaultPercentiles)
+	SampleSize         int           // Raw details kept in a uniform random reservoir (0 = none)
+	Window             time.Duration // Width of time-series windows (0 = DefaultWindow); doubled as often as a long run needs
+}
+
+// Collector aggregates benchmark metrics as they are recorded. Memory is
+// bounded by the number of stages, endpoints and base URLs and the spread of
+// latencies, not by the number of requests or the length of the run: the
+// time series keeps at most maxWindows windows, widening them on long runs.
+// Raw details are only kept in the optional sample.
 type Collector struct {
-	mutex       sync.Mutex
-	allRequests []MetricDetail // Stores all individual request details
+	mutex      sync.Mutex
+	opts       Options
+	total      *aggregator
+	byStage    map[string]*aggregator // Keyed by MetricDetail.Stage
+	byEndpoint map[string]*aggregator // Keyed by MetricDetail.Endpoint
+	byBaseURL  map[string]*aggregator // Keyed by MetricDetail.BaseURL
+	windows    *windowSeries          // Results by completion time
+	timings    *timingAggregator      // HTTP phase distributions over all requests
+	stages     []stageSpan            // Stages in the order they began
+	samples    *reservoir             // nil when Options.SampleSize is 0
+	traces     traceTracker           // Slowest and failed traced requests
+	sinks      []Sink                 // Receive every detail as it is recorded
 }
 
-// NewCollector creates a new metrics collector
+// Sink receives each detail as it is recorded, for example to stream raw
+// samples to disk. Record is called with the collector's lock held, in
+// recording order, and must not call back into the Collector.
+type Sink interface {
+	Record(detail MetricDetail)
+}
+
+// NewCollector creates a new metrics collector with default options
 func NewCollector() *Collector {
-	return &Collector{
-		allRequests: make([]MetricDetail, 0),
+	return NewCollectorWithOptions(Options{})
+}
+
+// NewCollectorWithOptions creates a new metrics collector
+func NewCollectorWithOptions(opts Options) *Collector {
+	if opts.HistogramPrecision == 0 {
+		opts.HistogramPrecision = DefaultHistogramPrecision
+	}
+	if len(opts.Percentiles) == 0 {
+		opts.Percentiles = DefaultPercentiles
 	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	c := &Collector{
+		opts:       opts,
+		total:      newAggregator(opts.HistogramPrecision),
+		byStage:    make(map[string]*aggregator),
+		byEndpoint: make(map[string]*aggregator),
+		byBaseURL:  make(map[string]*aggregator),
+		windows:    newWindowSeries(opts.Window, opts.HistogramPrecision),
+		timings:    newTimingAggregator(opts.HistogramPrecision),
+	}
+	if opts.SampleSize > 0 {
+		c.samples = newReservoir(opts.SampleSize)
+	}
+	return c
 }
 
 // RecordRequest records the result of a single HTTP request
 func (c *Collector) RecordRequest(url string, method string, statusCode int, duration time.Duration, isError bool, errorMsg string) {
+	c.AppendDetail(MetricDetail{
+		URL:          url,
+		Method:       method,
+		StatusCode:   statusCode,
+		Duration:     duration,
+		IsError:      isError,
+		ErrorMsg:     errorMsg,
+		Timestamp:    time.Now(),
+		ResponseTime: duration,
+	})
+}
+
+// AppendDetail records a MetricDetail in the collector (thread-safe).
+func (c *Collector) AppendDetail(detail MetricDetail) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	c.allRequests = append(c.allRequests, MetricDetail{
-		URL:        url,
-		Method:     method,
-		StatusCode: statusCode,
-		Duration:   duration,
-		IsError:    isError,
-		ErrorMsg:   errorMsg,
-		Timestamp:  time.Now(),
-	})
+	c.total.add(detail)
+	c.addTo(c.byStage, detail.Stage, detail)
+	c.addTo(c.byEndpoint, detail.Endpoint, detail)
+	c.addTo(c.byBaseURL, detail.BaseURL, detail)
+	c.windows.add(detail)
+	c.timings.add(detail.Timings)
+	if c.samples != nil {
+		c.samples.offer(detail)
+	}
+	c.traces.add(detail)
+	for _, sink := range c.sinks {
+		sink.Record(detail)
+	}
 }
 
-// AppendDetail appends a MetricDetail to the collector (thread-safe).
-func (c *Collector) AppendDetail(detail MetricDetail) {
+// RecordMissed records the corrected response time of a scheduled request
+// that was dropped because the tool could not keep up. It counts towards the
+// response-time distribution of the run and of stage, so a stall that fills
+// the queue is not hidden, but not towards requests, endpoints or sinks.
+func (c *Collector) RecordMissed(stage string, responseTime time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.total.addMissed(responseTime)
+	if stage == "" {
+		return
+	}
+	agg := c.byStage[stage]
+	if agg == nil {
+		agg = newAggregator(c.opts.HistogramPrecision)
+		c.byStage[stage] = agg
+	}
+	agg.addMissed(responseTime)
+}
+
+// AddSink registers a sink for every detail recorded from now on.
+func (c *Collector) AddSink(sink Sink) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.sinks = append(c.sinks, sink)
+}
+
+// addTo folds detail into the group's aggregator; an empty key is not grouped
+func (c *Collector) addTo(groups map[string]*aggregator, key string, detail MetricDetail) {
+	if key == "" {
+		return
+	}
+	agg := groups[key]
+	if agg == nil {
+		agg = newAggregator(c.opts.HistogramPrecision)
+		groups[key] = agg
+	}
+	agg.add(detail)
+}
+
+// Samples returns a copy of the raw-detail reservoir: a uniform random sample
+// of at most Options.SampleSize recorded requests (nil when sampling is off).
+func (c *Collector) Samples() []MetricDetail {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
-	c.allRequests = append(c.allRequests, detail)
+	if c.samples == nil {
+		return nil
+	}
+	return c.samples.snapshot()
+}
+
+// BeginStage marks the start of a named load stage. Requests whose Stage
+// matches the name are summarised separately in AggregatedResults.Stages.
+func (c *Collector) BeginStage(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.stages = append(c.stages, stageSpan{name: name, start: time.Now()})
+}
+
+// EndStage marks the end of the most recent stage with the given name.
+func (c *Collector) EndStage(name string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := len(c.stages) - 1; i >= 0; i-- {
+		if c.stages[i].name == name {
+			c.stages[i].end = time.Now()
+			return
+		}
+	}
+}
+
+// StageResults summarises the requests scheduled during one load stage.
+type StageResults struct {
+	Name        string
+	Duration    time.Duration // Wall-clock length of the stage (so far, if still running)
+	AchievedRPS float64       // Completed requests per second over Duration
+	Results     AggregatedResults
 }
 
 // AggregatedResults provides a summary of all collected metrics.
@@ -58,14 +225,70 @@ type AggregatedResults struct {
 	TotalRequests      int64
 	SuccessfulRequests int64
 	FailedRequests     int64
-	TotalDuration      time.Duration // Sum of all request durations
+	TotalDuration      time.Duration // Sum of all request durations (service time)
 	AvgDuration        time.Duration
 	MinDuration        time.Duration
 	MaxDuration        time.Duration
+	TotalResponseTime  time.Duration // Sum of corrected response times (intended start to completion), dropped slots included
+	AvgResponseTime    time.Duration
+	MaxResponseTime    time.Duration
 	StatusCodesCount   map[int]int64  // Counts per status code
 	ErrorDetails       map[string]int // Count of specific error messages
-	// TODO: Add latencies (p50, p90, p95, p99)
-	// TODO: Add RPS achieved
+	Elapsed            time.Duration  // From the first request start to the last completion
+	AchievedRPS        float64        // Completed requests per second over Elapsed
+	// Request and response body bytes, their per-request averages, and the
+	// achieved network throughput in bytes per second over Elapsed
+	BytesSent         int64
+	BytesReceived     int64
+	AvgBytesSent      int64
+	AvgBytesReceived  int64
+	SentPerSecond     float64
+	ReceivedPerSecond float64
+	Stages            []StageResults // Per-stage breakdown, in stage order (staged modes only)
+	// Per-endpoint and per-base-URL breakdowns, keyed by endpoint name and base URL
+	// (top-level results only)
+	Endpoints map[string]AggregatedResults
+	BaseURLs  map[string]AggregatedResults
+	// Windows is the time series of results by completion time, in fixed
+	// windows of Options.Window (top-level results only)
+	Windows []WindowResults
+	// Timings are the HTTP phase distributions, in TimingPhases order, and
+	// ReusedConnections counts requests that skipped connection setup
+	// (top-level results only)
+	Timings           []TimingResults
+	ReusedConnections int64
+	// Samples is the raw-request reservoir, nil unless Options.SampleSize
+	// is set (top-level results only)
+	Samples []MetricDetail
+	// SlowestTraced and FailedTraced hold the slowest requests (slowest
+	// first) and the first failed ones that carried a trace ID, at most
+	// NotableTraces of each (top-level results only)
+	SlowestTraced []MetricDetail
+	FailedTraced  []MetricDetail
+	// Percentiles of service time and corrected response time, in configured order
+	Percentiles         []PercentileValue
+	ResponsePercentiles []PercentileValue
+	// Latency and ResponseLatency hold the full distributions; they can be
+	// merged across runs or queried for percentiles that were not configured.
+	Latency         *Histogram
+	ResponseLatency *Histogram
+}
+
+// Percentile returns the p-th percentile (0-100) of service time, or 0 when
+// nothing was recorded.
+func (r AggregatedResults) Percentile(p float64) time.Duration {
+	if r.Latency == nil {
+		return 0
+	}
+	return r.Latency.ValueAtPercentile(p)
+}
+
+// ResponsePercentile returns the p-th percentile (0-100) of corrected response time.
+func (r AggregatedResults) ResponsePercentile(p float64) time.Duration {
+	if r.ResponseLatency == nil {
+		return 0
+	}
+	return r.ResponseLatency.ValueAtPercentile(p)
 }
 
 // GetResults processes the collected metrics and returns an aggregated summary.
@@ -73,46 +296,63 @@ func (c *Collector) GetResults() AggregatedResults {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
-	if len(c.allRequests) == 0 {
-		return AggregatedResults{ // Return empty/zeroed struct if no requests
-			StatusCodesCount: make(map[int]int64),
-			ErrorDetails:     make(map[string]int),
-		}
+	res := c.total.results(c.opts.Percentiles)
+	res.Endpoints = c.groupResults(c.byEndpoint)
+	res.BaseURLs = c.groupResults(c.byBaseURL)
+	res.Windows = c.windows.results(c.opts.Percentiles)
+	res.Timings = c.timings.results(c.opts.Percentiles)
+	res.ReusedConnections = c.timings.reused
+	if c.samples != nil {
+		res.Samples = c.samples.snapshot()
+	}
+	res.SlowestTraced = c.traces.slowestFirst()
+	res.FailedTraced = c.traces.failedInOrder()
+	if len(c.stages) == 0 {
+		return res
 	}
 
-	var res AggregatedResults
-	res.StatusCodesCount = make(map[int]int64)
-	res.ErrorDetails = make(map[string]int)
-	res.MinDuration = c.allRequests[0].Duration // Initialize with the first request
-
-	for _, r := range c.allRequests {
-		res.TotalRequests++
-		res.TotalDuration += r.Duration
-
-		if r.IsError || r.StatusCode >= 400 {
-			res.FailedRequests++
-			if r.ErrorMsg != "" {
-				res.ErrorDetails[r.ErrorMsg]++
-			}
-		} else {
-			res.SuccessfulRequests++
+	now := time.Now()
+	res.Stages = make([]StageResults, 0, len(c.stages))
+	for _, st := range c.stages {
+		end := st.end
+		if end.IsZero() {
+			end = now
 		}
-
-		res.StatusCodesCount[r.StatusCode]++
-
-		if r.Duration < res.MinDuration {
-			res.MinDuration = r.Duration
+		sr := StageResults{
+			Name:     st.name,
+			Duration: end.Sub(st.start),
+			Results:  c.stageResults(st.name),
 		}
-		if r.Duration > res.MaxDuration {
-			res.MaxDuration = r.Duration
+		if sr.Duration > 0 {
+			sr.AchievedRPS = float64(sr.Results.TotalRequests) / sr.Duration.Seconds()
 		}
+		res.Stages = append(res.Stages, sr)
 	}
+	return res
+}
 
-	if res.TotalRequests > 0 {
-		res.AvgDuration = res.TotalDuration / time.Duration(res.TotalRequests)
-	} else {
-		res.MinDuration = 0 // Avoid returning the initial large value if no requests
+// responseTime returns the corrected response time, falling back to the service time
+func (d MetricDetail) responseTime() time.Duration {
+	if d.ResponseTime == 0 {
+		return d.Duration
 	}
+	return d.ResponseTime
+}
 
-	return res
+// stageResults summarises the requests recorded for the named stage
+func (c *Collector) stageResults(name string) AggregatedResults {
+	agg := c.byStage[name]
+	if agg == nil {
+		agg = newAggregator(c.opts.HistogramPrecision)
+	}
+	return agg.results(c.opts.Percentiles)
+}
+
+// groupResults summarises every group of a breakdown
+func (c *Collector) groupResults(groups map[string]*aggregator) map[string]AggregatedResults {
+	out := make(map[string]AggregatedResults, len(groups))
+	for key, agg := range groups {
+		out[key] = agg.results(c.opts.Percentiles)
+	}
+	return out
 }
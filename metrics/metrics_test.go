@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -464,3 +465,426 @@ func TestAggregatedResults_Structure(t *testing.T) {
 		t.Error("Failed to set ErrorDetails")
 	}
 }
+
+func TestCollector_StageBreakdown(t *testing.T) {
+	collector := NewCollector()
+
+	collector.BeginStage("warmup")
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 10 * time.Millisecond, Stage: "warmup"})
+	collector.AppendDetail(MetricDetail{StatusCode: 500, Duration: 30 * time.Millisecond, Stage: "warmup"})
+	collector.EndStage("warmup")
+
+	collector.BeginStage("peak")
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 50 * time.Millisecond, Stage: "peak"})
+	collector.EndStage("peak")
+
+	results := collector.GetResults()
+	if results.TotalRequests != 3 {
+		t.Fatalf("Expected 3 total requests, got %d", results.TotalRequests)
+	}
+	if len(results.Stages) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(results.Stages))
+	}
+
+	warmup := results.Stages[0]
+	if warmup.Name != "warmup" {
+		t.Errorf("Expected first stage 'warmup', got %q", warmup.Name)
+	}
+	if warmup.Results.TotalRequests != 2 || warmup.Results.FailedRequests != 1 {
+		t.Errorf("Unexpected warmup counts: %+v", warmup.Results)
+	}
+	if warmup.Results.AvgDuration != 20*time.Millisecond {
+		t.Errorf("Expected warmup avg 20ms, got %v", warmup.Results.AvgDuration)
+	}
+	if warmup.Duration <= 0 || warmup.AchievedRPS <= 0 {
+		t.Errorf("Expected positive stage duration and RPS, got %v / %v", warmup.Duration, warmup.AchievedRPS)
+	}
+
+	peak := results.Stages[1]
+	if peak.Results.TotalRequests != 1 || peak.Results.MinDuration != 50*time.Millisecond {
+		t.Errorf("Unexpected peak results: %+v", peak.Results)
+	}
+}
+
+func TestCollector_AchievedRPS(t *testing.T) {
+	collector := NewCollector()
+	base := time.Now()
+
+	// Four requests completing over a 2s window (first starts at base, last ends at base+2s).
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 100 * time.Millisecond, Timestamp: base.Add(100 * time.Millisecond)})
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 100 * time.Millisecond, Timestamp: base.Add(700 * time.Millisecond)})
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 100 * time.Millisecond, Timestamp: base.Add(1300 * time.Millisecond)})
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 100 * time.Millisecond, Timestamp: base.Add(2 * time.Second)})
+
+	results := collector.GetResults()
+	if results.Elapsed != 2*time.Second {
+		t.Errorf("Elapsed = %v, want 2s", results.Elapsed)
+	}
+	if results.AchievedRPS != 2 {
+		t.Errorf("AchievedRPS = %v, want 2", results.AchievedRPS)
+	}
+}
+
+// within reports whether got is within 0.1% of want (the default histogram precision).
+func within(got, want time.Duration) bool {
+	diff := got - want
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= want/1000
+}
+
+func TestCollector_Percentiles(t *testing.T) {
+	collector := NewCollectorWithOptions(Options{Percentiles: []float64{50, 99, 99.9}})
+	collector.BeginStage("step-1")
+	for i := 1; i <= 1000; i++ {
+		collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: time.Duration(i) * time.Millisecond, Stage: "step-1"})
+	}
+	collector.EndStage("step-1")
+	collector.BeginStage("step-2")
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 5 * time.Second, Stage: "step-2"})
+	collector.EndStage("step-2")
+
+	results := collector.GetResults()
+	if len(results.Percentiles) != 3 {
+		t.Fatalf("len(Percentiles) = %d, want 3", len(results.Percentiles))
+	}
+	// 1001 samples overall: nearest rank picks the 501st, 991st and 1000th.
+	for i, want := range []time.Duration{501 * time.Millisecond, 991 * time.Millisecond, 1000 * time.Millisecond} {
+		pv := results.Percentiles[i]
+		if !within(pv.Value, want) {
+			t.Errorf("%s = %v, want ~%v", PercentileLabel(pv.Percentile), pv.Value, want)
+		}
+	}
+	if got := results.Percentile(100); got != 5*time.Second {
+		t.Errorf("p100 = %v, want 5s", got)
+	}
+
+	step1 := results.Stages[0].Results
+	if got := step1.Percentile(99); !within(got, 990*time.Millisecond) {
+		t.Errorf("step-1 p99 = %v, want ~990ms", got)
+	}
+	if got := results.Stages[1].Results.Percentile(50); got != 5*time.Second {
+		t.Errorf("step-2 p50 = %v, want 5s", got)
+	}
+	if got := (AggregatedResults{}).Percentile(99); got != 0 {
+		t.Errorf("empty p99 = %v, want 0", got)
+	}
+}
+
+func TestCollector_CorrectedResponseTime(t *testing.T) {
+	collector := NewCollector()
+	intended := time.Now()
+
+	// Waited 40ms in the queue, then took 10ms to serve.
+	collector.AppendDetail(MetricDetail{
+		StatusCode:    200,
+		Duration:      10 * time.Millisecond,
+		IntendedStart: intended,
+		Timestamp:     intended.Add(50 * time.Millisecond),
+		ResponseTime:  50 * time.Millisecond,
+	})
+	// No intended start recorded: response time falls back to service time.
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 30 * time.Millisecond})
+
+	results := collector.GetResults()
+	if results.AvgDuration != 20*time.Millisecond {
+		t.Errorf("AvgDuration = %v, want 20ms", results.AvgDuration)
+	}
+	if results.AvgResponseTime != 40*time.Millisecond {
+		t.Errorf("AvgResponseTime = %v, want 40ms", results.AvgResponseTime)
+	}
+	if results.MaxResponseTime != 50*time.Millisecond {
+		t.Errorf("MaxResponseTime = %v, want 50ms", results.MaxResponseTime)
+	}
+}
+
+func TestCollector_RecordMissed(t *testing.T) {
+	collector := NewCollector()
+	collector.BeginStage("peak")
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Stage: "peak", Endpoint: "root", Duration: 10 * time.Millisecond})
+	collector.RecordMissed("peak", 290*time.Millisecond)
+
+	results := collector.GetResults()
+	if results.TotalRequests != 1 || results.Latency.Count() != 1 || results.Endpoints["root"].ResponseLatency.Count() != 1 {
+		t.Errorf("a missed slot is not a request: %d requests, %d service times", results.TotalRequests, results.Latency.Count())
+	}
+	if results.ResponseLatency.Count() != 2 || results.AvgResponseTime != 150*time.Millisecond || results.MaxResponseTime != 290*time.Millisecond {
+		t.Errorf("expected the missed slot in the response times, got %d samples, avg %v, max %v",
+			results.ResponseLatency.Count(), results.AvgResponseTime, results.MaxResponseTime)
+	}
+	if stage := results.Stages[0].Results; stage.ResponseLatency.Count() != 2 {
+		t.Errorf("expected the missed slot in its stage, got %d samples", stage.ResponseLatency.Count())
+	}
+}
+
+func TestCollector_SampleReservoir(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordRequest("url", "GET", 200, time.Millisecond, false, "")
+	if collector.Samples() != nil {
+		t.Fatal("Samples should be nil when sampling is off")
+	}
+
+	collector = NewCollectorWithOptions(Options{SampleSize: 100})
+	for i := range 10_000 {
+		collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: time.Duration(i) * time.Microsecond})
+	}
+	samples := collector.Samples()
+	if len(samples) != 100 {
+		t.Fatalf("Expected 100 samples, got %d", len(samples))
+	}
+	// A uniform sample should not be stuck on the first requests
+	late := 0
+	for _, s := range samples {
+		if s.Duration >= 5000*time.Microsecond {
+			late++
+		}
+	}
+	if late < 25 || late > 75 {
+		t.Errorf("Expected about half the samples from the second half of the run, got %d", late)
+	}
+	res := collector.GetResults()
+	if res.TotalRequests != 10_000 {
+		t.Error("Sampling should not affect aggregated totals")
+	}
+	if len(res.Samples) != 100 {
+		t.Errorf("Expected the sample in the results, got %d", len(res.Samples))
+	}
+}
+
+func TestCollector_ErrorKindsBounded(t *testing.T) {
+	collector := NewCollector()
+	for i := range maxErrorKinds + 50 {
+		collector.RecordRequest("url", "GET", 0, time.Millisecond, true, fmt.Sprintf("dial tcp 127.0.0.1:%d: refused", i))
+	}
+	collector.RecordRequest("url", "GET", 0, time.Millisecond, true, "dial tcp 127.0.0.1:0: refused")
+
+	results := collector.GetResults()
+	if len(results.ErrorDetails) != maxErrorKinds+1 {
+		t.Fatalf("Expected %d error kinds, got %d", maxErrorKinds+1, len(results.ErrorDetails))
+	}
+	if results.ErrorDetails[OtherErrorsKey] != 50 {
+		t.Errorf("Expected 50 overflow errors, got %d", results.ErrorDetails[OtherErrorsKey])
+	}
+	if results.ErrorDetails["dial tcp 127.0.0.1:0: refused"] != 2 {
+		t.Error("Messages seen before the cap should keep counting")
+	}
+}
+
+func TestCollector_ResultsAreSnapshots(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordRequest("url", "GET", 200, time.Millisecond, false, "")
+	before := collector.GetResults()
+	collector.RecordRequest("url", "GET", 500, time.Second, false, "")
+
+	if before.StatusCodesCount[500] != 0 || before.Latency.Count() != 1 {
+		t.Error("Earlier results should not change as recording continues")
+	}
+}
+
+func TestCollector_EndpointAndBaseURLBreakdown(t *testing.T) {
+	collector := NewCollector()
+	add := func(endpoint, baseURL string, status int, d time.Duration) {
+		collector.AppendDetail(MetricDetail{Endpoint: endpoint, BaseURL: baseURL, StatusCode: status, Duration: d, Timestamp: time.Now()})
+	}
+	add("get_user", "http://a", 200, 10*time.Millisecond)
+	add("get_user", "http://b", 200, 30*time.Millisecond)
+	add("create_user", "http://a", 500, 100*time.Millisecond)
+	collector.RecordRequest("url", "GET", 200, time.Millisecond, false, "") // no endpoint: totals only
+
+	results := collector.GetResults()
+	if results.TotalRequests != 4 {
+		t.Fatalf("Expected 4 total requests, got %d", results.TotalRequests)
+	}
+	if len(results.Endpoints) != 2 || len(results.BaseURLs) != 2 {
+		t.Fatalf("Expected 2 endpoints and 2 base URLs, got %d and %d", len(results.Endpoints), len(results.BaseURLs))
+	}
+
+	getUser := results.Endpoints["get_user"]
+	if getUser.TotalRequests != 2 || getUser.AvgDuration != 20*time.Millisecond {
+		t.Errorf("Unexpected get_user results: %d requests, avg %v", getUser.TotalRequests, getUser.AvgDuration)
+	}
+	if got := getUser.Percentile(100); got != 30*time.Millisecond {
+		t.Errorf("Expected get_user p100 30ms, got %v", got)
+	}
+	if results.Endpoints["create_user"].FailedRequests != 1 {
+		t.Error("Expected create_user failure to be attributed to its endpoint")
+	}
+
+	hostA := results.BaseURLs["http://a"]
+	if hostA.TotalRequests != 2 || hostA.FailedRequests != 1 {
+		t.Errorf("Unexpected http://a results: %d requests, %d failed", hostA.TotalRequests, hostA.FailedRequests)
+	}
+	if hostA.Endpoints != nil || hostA.Stages != nil {
+		t.Error("Breakdown entries should not nest further breakdowns")
+	}
+}
+
+func TestCollector_Windows(t *testing.T) {
+	collector := NewCollectorWithOptions(Options{Window: 10 * time.Second})
+	base := time.Unix(1_700_000_000, 0) // aligned to a 10s boundary
+	add := func(offset time.Duration, status int, d time.Duration) {
+		collector.AppendDetail(MetricDetail{StatusCode: status, Duration: d, Timestamp: base.Add(offset)})
+	}
+	add(1*time.Second, 200, 10*time.Millisecond)
+	add(9*time.Second, 500, 30*time.Millisecond)
+	add(35*time.Second, 200, 50*time.Millisecond)         // leaves the 10-20s and 20-30s windows empty
+	collector.AppendDetail(MetricDetail{StatusCode: 200}) // no timestamp: totals only
+
+	windows := collector.GetResults().Windows
+	if len(windows) != 4 {
+		t.Fatalf("Expected 4 windows (gaps filled), got %d", len(windows))
+	}
+	first := windows[0]
+	if !first.Start.Equal(base) || first.Duration != 10*time.Second {
+		t.Errorf("Unexpected first window %v +%v", first.Start, first.Duration)
+	}
+	if first.Results.TotalRequests != 2 || first.Results.FailedRequests != 1 || first.AchievedRPS != 0.2 {
+		t.Errorf("Unexpected first window results: %d requests, %d failed, %v RPS",
+			first.Results.TotalRequests, first.Results.FailedRequests, first.AchievedRPS)
+	}
+	if got := first.Results.Percentile(100); got != 30*time.Millisecond {
+		t.Errorf("Expected first window p100 30ms, got %v", got)
+	}
+	for _, w := range windows[1:3] {
+		if w.Results.TotalRequests != 0 || len(w.Results.Percentiles) != len(DefaultPercentiles) {
+			t.Errorf("Expected an empty window at %v with zero percentiles, got %d requests", w.Start, w.Results.TotalRequests)
+		}
+	}
+	if last := windows[3]; !last.Start.Equal(base.Add(30*time.Second)) || last.Results.TotalRequests != 1 {
+		t.Errorf("Unexpected last window at %v with %d requests", last.Start, last.Results.TotalRequests)
+	}
+}
+
+func TestCollector_WindowsBounded(t *testing.T) {
+	collector := NewCollector()
+	base := time.Unix(1_700_000_000, 0)
+	// Three hours at 10 requests per second would be 10,800 one-second windows
+	const seconds, perSecond = 3 * 60 * 60, 10
+	for sec := range seconds {
+		for i := range perSecond {
+			collector.AppendDetail(MetricDetail{
+				StatusCode: 200,
+				Duration:   time.Duration(i+1) * time.Millisecond,
+				Timestamp:  base.Add(time.Duration(sec)*time.Second + time.Duration(i)*time.Millisecond),
+			})
+		}
+	}
+	if n := len(collector.windows.buckets); n > maxWindows {
+		t.Fatalf("expected at most %d windows kept, got %d", maxWindows, n)
+	}
+
+	windows := collector.GetResults().Windows
+	if len(windows) > maxWindows || windows[0].Duration != 16*time.Second {
+		t.Fatalf("expected at most %d windows of 16s, got %d of %v", maxWindows, len(windows), windows[0].Duration)
+	}
+	var total int64
+	for _, w := range windows {
+		total += w.Results.TotalRequests
+	}
+	if total != seconds*perSecond {
+		t.Fatalf("expected merged windows to keep all %d requests, got %d", seconds*perSecond, total)
+	}
+	if full := windows[1]; full.Results.TotalRequests != 16*perSecond || full.AchievedRPS != perSecond || full.Results.Percentile(100) != 10*time.Millisecond {
+		t.Fatalf("unexpected merged window: %d requests, %v RPS, p100 %v",
+			full.Results.TotalRequests, full.AchievedRPS, full.Results.Percentile(100))
+	}
+}
+
+func TestCollector_Timings(t *testing.T) {
+	collector := NewCollector()
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 80 * time.Millisecond, Timings: RequestTimings{
+		TCPConnect: 10 * time.Millisecond, TimeToFirstByte: 60 * time.Millisecond, Download: 10 * time.Millisecond,
+	}})
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: 40 * time.Millisecond, Timings: RequestTimings{
+		TimeToFirstByte: 30 * time.Millisecond, Download: 10 * time.Millisecond, ConnReused: true,
+	}})
+
+	results := collector.GetResults()
+	if len(results.Timings) != len(TimingPhases) {
+		t.Fatalf("Expected %d phases, got %d", len(TimingPhases), len(results.Timings))
+	}
+	byPhase := make(map[string]TimingResults)
+	for _, tr := range results.Timings {
+		byPhase[tr.Phase] = tr
+	}
+	if connect := byPhase["TCP Connect"]; connect.Count != 1 || connect.Avg != 10*time.Millisecond {
+		t.Errorf("TCP Connect should only count the new connection, got %d samples avg %v", connect.Count, connect.Avg)
+	}
+	if ttfb := byPhase["Time to First Byte"]; ttfb.Count != 2 || ttfb.Avg != 45*time.Millisecond {
+		t.Errorf("Unexpected time to first byte: %d samples avg %v", ttfb.Count, ttfb.Avg)
+	}
+	if byPhase["DNS Lookup"].Count != 0 {
+		t.Error("DNS Lookup should have no samples")
+	}
+	if results.ReusedConnections != 1 {
+		t.Errorf("Expected 1 reused connection, got %d", results.ReusedConnections)
+	}
+}
+
+func TestCollector_BytesTransferred(t *testing.T) {
+	collector := NewCollector()
+	base := time.Now()
+	collector.AppendDetail(MetricDetail{Endpoint: "upload", StatusCode: 201, Duration: time.Second, Timestamp: base.Add(time.Second), BytesSent: 3000, BytesReceived: 100})
+	collector.AppendDetail(MetricDetail{Endpoint: "download", StatusCode: 200, Duration: time.Second, Timestamp: base.Add(2 * time.Second), BytesReceived: 9900})
+
+	results := collector.GetResults()
+	if results.BytesSent != 3000 || results.BytesReceived != 10000 {
+		t.Fatalf("Expected 3000 B sent and 10000 B received, got %d and %d", results.BytesSent, results.BytesReceived)
+	}
+	if results.AvgBytesSent != 1500 || results.AvgBytesReceived != 5000 {
+		t.Errorf("Unexpected averages: %d sent, %d received", results.AvgBytesSent, results.AvgBytesReceived)
+	}
+	// Two seconds from the first start to the last completion
+	if results.SentPerSecond != 1500 || results.ReceivedPerSecond != 5000 {
+		t.Errorf("Unexpected throughput: %v B/s sent, %v B/s received", results.SentPerSecond, results.ReceivedPerSecond)
+	}
+	if up := results.Endpoints["upload"]; up.BytesSent != 3000 || up.BytesReceived != 100 {
+		t.Errorf("Unexpected upload bytes: %d sent, %d received", up.BytesSent, up.BytesReceived)
+	}
+}
+
+type recordingSink struct{ details []MetricDetail }
+
+func (s *recordingSink) Record(d MetricDetail) { s.details = append(s.details, d) }
+
+func TestCollector_AddSink(t *testing.T) {
+	collector := NewCollector()
+	collector.RecordRequest("before", "GET", 200, time.Millisecond, false, "")
+	sink := &recordingSink{}
+	collector.AddSink(sink)
+	collector.RecordRequest("a", "GET", 200, time.Millisecond, false, "")
+	collector.AppendDetail(MetricDetail{URL: "b", StatusCode: 500})
+
+	if len(sink.details) != 2 || sink.details[0].URL != "a" || sink.details[1].URL != "b" {
+		t.Fatalf("Sink should see details recorded after it was added, in order; got %+v", sink.details)
+	}
+}
+
+func TestCollector_NotableTraces(t *testing.T) {
+	collector := NewCollector()
+	collector.AppendDetail(MetricDetail{StatusCode: 200, Duration: time.Hour}) // Untraced
+	for i := range 30 {
+		status := 200
+		if i%5 == 0 {
+			status = 503
+		}
+		collector.AppendDetail(MetricDetail{
+			TraceID: fmt.Sprintf("%032x", i), StatusCode: status,
+			Duration: time.Duration((i*7)%30+1) * time.Millisecond,
+		})
+	}
+
+	res := collector.GetResults()
+	if len(res.SlowestTraced) != NotableTraces {
+		t.Fatalf("expected %d slowest traces, got %d", NotableTraces, len(res.SlowestTraced))
+	}
+	for i, d := range res.SlowestTraced {
+		if want := time.Duration(30-i) * time.Millisecond; d.Duration != want {
+			t.Errorf("slowest[%d] = %v, want %v", i, d.Duration, want)
+		}
+	}
+	if len(res.FailedTraced) != 6 || res.FailedTraced[1].TraceID != fmt.Sprintf("%032x", 5) {
+		t.Errorf("expected the six failed requests in order, got %+v", res.FailedTraced)
+	}
+}
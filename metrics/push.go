@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPushInterval is how often a Pusher hands closed windows to its sink.
+const DefaultPushInterval = 5 * time.Second
+
+// pushGrace is how long a Pusher waits after a window ends before closing
+// it, so requests completing right at the boundary still land in it.
+const pushGrace = 100 * time.Millisecond
+
+// WindowPoint is one closed time window of results, for all requests
+// (Endpoint "") or a single endpoint.
+type WindowPoint struct {
+	Endpoint string
+	Window   WindowResults
+}
+
+// ResultsSink receives windowed aggregates in batches, for example to push
+// them to a time-series database. WriteWindows is called from a single
+// background goroutine, never from the request path, so a slow sink only
+// delays later batches.
+type ResultsSink interface {
+	WriteWindows(batch []WindowPoint) error
+}
+
+// PushOptions tunes a Pusher; zero values select the defaults.
+type PushOptions struct {
+	Window             time.Duration // Width of the aggregated windows (0 = DefaultWindow)
+	Interval           time.Duration // Time between batches (0 = DefaultPushInterval)
+	Percentiles        []float64     // Percentiles computed per window (nil = DefaultPercentiles)
+	HistogramPrecision int           // 0 = DefaultHistogramPrecision
+}
+
+// pushKey identifies the aggregate of one window and endpoint
+type pushKey struct {
+	start    int64 // Window start in Unix nanoseconds
+	endpoint string
+}
+
+// Pusher is a Sink that aggregates requests into fixed windows per endpoint
+// and periodically hands the closed ones to a ResultsSink. Record only
+// updates in-memory aggregates; writes happen in the background and windows
+// pile up while the sink is busy, to be sent together in the next batch.
+type Pusher struct {
+	sink ResultsSink
+	opts PushOptions
+
+	mu      sync.Mutex
+	pending map[pushKey]*aggregator
+
+	batches, failed int
+	lastErr         error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewPusher creates a pusher writing to sink; call Start to begin pushing.
+func NewPusher(sink ResultsSink, opts PushOptions) *Pusher {
+	if opts.Window <= 0 {
+		opts.Window = DefaultWindow
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultPushInterval
+	}
+	if len(opts.Percentiles) == 0 {
+		opts.Percentiles = DefaultPercentiles
+	}
+	if opts.HistogramPrecision == 0 {
+		opts.HistogramPrecision = DefaultHistogramPrecision
+	}
+	return &Pusher{
+		sink:    sink,
+		opts:    opts,
+		pending: make(map[pushKey]*aggregator),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Record folds a completed request into its window, for all requests and
+// for its endpoint; details without a timestamp are ignored.
+func (p *Pusher) Record(d MetricDetail) {
+	if d.Timestamp.IsZero() {
+		return
+	}
+	start := d.Timestamp.Truncate(p.opts.Window).UnixNano()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.addLocked(pushKey{start: start}, d)
+	if d.Endpoint != "" {
+		p.addLocked(pushKey{start: start, endpoint: d.Endpoint}, d)
+	}
+}
+
+func (p *Pusher) addLocked(key pushKey, d MetricDetail) {
+	agg := p.pending[key]
+	if agg == nil {
+		agg = newAggregator(p.opts.HistogramPrecision)
+		p.pending[key] = agg
+	}
+	agg.add(d)
+}
+
+// Start begins pushing closed windows every Interval
+func (p *Pusher) Start() {
+	go p.loop()
+}
+
+// Stop ends pushing and sends every remaining window, including the one
+// still open. It returns an error when any batch failed.
+func (p *Pusher) Stop() error {
+	close(p.stop)
+	<-p.done
+	p.push(time.Time{})
+	if p.failed > 0 {
+		return fmt.Errorf("%d of %d metric batches failed, last error: %w", p.failed, p.batches, p.lastErr)
+	}
+	return nil
+}
+
+func (p *Pusher) loop() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			p.push(now.Add(-pushGrace))
+		}
+	}
+}
+
+// push writes the windows that ended before cutoff (all of them when cutoff
+// is zero). A failed batch is counted and dropped.
+func (p *Pusher) push(cutoff time.Time) {
+	batch := p.take(cutoff)
+	if len(batch) == 0 {
+		return
+	}
+	p.batches++
+	if err := p.sink.WriteWindows(batch); err != nil {
+		p.failed++
+		p.lastErr = err
+	}
+}
+
+// take removes the closed windows from the pending set and summarises them,
+// ordered by time and then endpoint (all requests first).
+func (p *Pusher) take(cutoff time.Time) []WindowPoint {
+	width := p.opts.Window
+	p.mu.Lock()
+	closed := make(map[pushKey]*aggregator)
+	for key, agg := range p.pending {
+		if cutoff.IsZero() || key.start+int64(width) <= cutoff.UnixNano() {
+			closed[key] = agg
+			delete(p.pending, key)
+		}
+	}
+	p.mu.Unlock()
+
+	keys := make([]pushKey, 0, len(closed))
+	for key := range closed {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].start != keys[j].start {
+			return keys[i].start < keys[j].start
+		}
+		return keys[i].endpoint < keys[j].endpoint
+	})
+
+	batch := make([]WindowPoint, 0, len(keys))
+	for _, key := range keys {
+		res := closed[key].results(p.opts.Percentiles)
+		batch = append(batch, WindowPoint{
+			Endpoint: key.endpoint,
+			Window: WindowResults{
+				Start:       time.Unix(0, key.start),
+				Duration:    width,
+				AchievedRPS: float64(res.TotalRequests) / width.Seconds(),
+				Results:     res,
+			},
+		})
+	}
+	return batch
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingSink records batches, blocking each write until release is closed
+type blockingSink struct {
+	release chan struct{}
+	mu      sync.Mutex
+	batches [][]WindowPoint
+	err     error
+}
+
+func (s *blockingSink) WriteWindows(batch []WindowPoint) error {
+	<-s.release
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, batch)
+	return s.err
+}
+
+func (s *blockingSink) points() []WindowPoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var all []WindowPoint
+	for _, b := range s.batches {
+		all = append(all, b...)
+	}
+	return all
+}
+
+func TestPusher_Batches(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	close(sink.release)
+	p := NewPusher(sink, PushOptions{Window: time.Second, Interval: time.Hour, Percentiles: []float64{50}})
+
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	p.Record(MetricDetail{Endpoint: "a", StatusCode: 200, Duration: 10 * time.Millisecond, Timestamp: base.Add(100 * time.Millisecond)})
+	p.Record(MetricDetail{Endpoint: "b", StatusCode: 500, Duration: 30 * time.Millisecond, Timestamp: base.Add(900 * time.Millisecond)})
+	p.Record(MetricDetail{Endpoint: "a", StatusCode: 200, Duration: 20 * time.Millisecond, Timestamp: base.Add(1500 * time.Millisecond)})
+	p.Record(MetricDetail{StatusCode: 200, Duration: time.Millisecond}) // No timestamp
+
+	// Only the first window has closed at the cutoff
+	p.push(base.Add(1200 * time.Millisecond))
+	got := sink.points()
+	if len(got) != 3 {
+		t.Fatalf("expected the total and two endpoints of the first window, got %d points", len(got))
+	}
+	if got[0].Endpoint != "" || got[0].Window.Results.TotalRequests != 2 || got[0].Window.Results.FailedRequests != 1 {
+		t.Errorf("first point should cover all requests of the window, got %+v", got[0])
+	}
+	if got[1].Endpoint != "a" || got[2].Endpoint != "b" || !got[1].Window.Start.Equal(base) || got[1].Window.AchievedRPS != 1 {
+		t.Errorf("unexpected endpoint points %+v, %+v", got[1], got[2])
+	}
+
+	p.Start()
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	got = sink.points()
+	if len(got) != 5 || !got[3].Window.Start.Equal(base.Add(time.Second)) || got[4].Window.Results.Percentiles[0].Value != 20*time.Millisecond {
+		t.Errorf("Stop should flush the open window, got %+v", got[3:])
+	}
+}
+
+func TestPusher_SlowSinkDoesNotBlockRecord(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{})}
+	p := NewPusher(sink, PushOptions{Window: 10 * time.Millisecond, Interval: 5 * time.Millisecond})
+	p.Start()
+
+	recorded := make(chan struct{})
+	go func() {
+		defer close(recorded)
+		for i := range 200 {
+			p.Record(MetricDetail{Endpoint: "a", StatusCode: 200, Duration: time.Millisecond, Timestamp: time.Now()})
+			if i%20 == 0 {
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+	}()
+	select {
+	case <-recorded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Record blocked on a slow sink")
+	}
+
+	close(sink.release)
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	var total int64
+	for _, pt := range sink.points() {
+		if pt.Endpoint == "" {
+			total += pt.Window.Results.TotalRequests
+		}
+	}
+	if total != 200 {
+		t.Errorf("every request should be pushed once, got %d", total)
+	}
+}
+
+func TestPusher_StopReportsFailures(t *testing.T) {
+	sink := &blockingSink{release: make(chan struct{}), err: errors.New("connection refused")}
+	close(sink.release)
+	p := NewPusher(sink, PushOptions{Interval: time.Hour})
+	p.Start()
+	p.Record(MetricDetail{StatusCode: 200, Timestamp: time.Now()})
+	err := p.Stop()
+	if err == nil || !errors.Is(err, sink.err) || err.Error() != "1 of 1 metric batches failed, last error: connection refused" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
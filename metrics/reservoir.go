@@ -0,0 +1,32 @@
+package metrics
+
+import "math/rand/v2"
+
+// reservoir keeps a uniform random sample of at most cap(samples) details
+// (Vitter's algorithm R): after n offers, each one is retained with
+// probability size/n.
+type reservoir struct {
+	samples []MetricDetail
+	seen    int64
+}
+
+func newReservoir(size int) *reservoir {
+	return &reservoir{samples: make([]MetricDetail, 0, size)}
+}
+
+// offer considers one detail for the sample
+func (r *reservoir) offer(d MetricDetail) {
+	r.seen++
+	if len(r.samples) < cap(r.samples) {
+		r.samples = append(r.samples, d)
+		return
+	}
+	if i := rand.Int64N(r.seen); i < int64(len(r.samples)) {
+		r.samples[i] = d
+	}
+}
+
+// snapshot returns a copy of the current sample
+func (r *reservoir) snapshot() []MetricDetail {
+	return append([]MetricDetail(nil), r.samples...)
+}
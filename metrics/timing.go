@@ -0,0 +1,74 @@
+package metrics
+
+import "time"
+
+// RequestTimings splits a request's service time into HTTP phases. Setup
+// phases are zero when the request reused a pooled connection.
+type RequestTimings struct {
+	DNSLookup       time.Duration // Resolving the host name
+	TCPConnect      time.Duration // Establishing the TCP connection
+	TLSHandshake    time.Duration // TLS handshake (https only)
+	TimeToFirstByte time.Duration // From the request being written to the first response byte: server processing
+	Download        time.Duration // From the first response byte to the end of the body
+	ConnReused      bool          // Whether the request ran on a kept-alive connection
+}
+
+// TimingPhases names the phases of RequestTimings in the order they happen.
+var TimingPhases = []string{"DNS Lookup", "TCP Connect", "TLS Handshake", "Time to First Byte", "Download"}
+
+// phases returns the phase durations in TimingPhases order
+func (t RequestTimings) phases() [5]time.Duration {
+	return [5]time.Duration{t.DNSLookup, t.TCPConnect, t.TLSHandshake, t.TimeToFirstByte, t.Download}
+}
+
+// TimingResults is the distribution of one HTTP phase. Count only includes
+// requests in which the phase took place, so connection setup phases are
+// summarised over new connections only.
+type TimingResults struct {
+	Phase       string
+	Count       int64
+	Avg         time.Duration
+	Percentiles []PercentileValue
+	Latency     *Histogram
+}
+
+// timingAggregator keeps one histogram per HTTP phase
+type timingAggregator struct {
+	phases [5]*Histogram
+	reused int64
+}
+
+func newTimingAggregator(precision int) *timingAggregator {
+	t := &timingAggregator{}
+	for i := range t.phases {
+		t.phases[i] = NewHistogram(precision)
+	}
+	return t
+}
+
+// add records every phase that took place
+func (t *timingAggregator) add(timings RequestTimings) {
+	for i, d := range timings.phases() {
+		if d > 0 {
+			t.phases[i].Record(d)
+		}
+	}
+	if timings.ConnReused {
+		t.reused++
+	}
+}
+
+// results snapshots the phase distributions, in TimingPhases order
+func (t *timingAggregator) results(percentiles []float64) []TimingResults {
+	out := make([]TimingResults, len(t.phases))
+	for i, h := range t.phases {
+		out[i] = TimingResults{
+			Phase:       TimingPhases[i],
+			Count:       h.Count(),
+			Avg:         h.Mean(),
+			Percentiles: h.Percentiles(percentiles),
+			Latency:     h.Clone(),
+		}
+	}
+	return out
+}
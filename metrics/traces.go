@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"container/heap"
+	"sort"
+)
+
+// NotableTraces is how many of the slowest and of the failed requests are
+// kept with their trace IDs, so they can be looked up in a tracing backend.
+const NotableTraces = 10
+
+// traceTracker keeps the slowest traced requests and the first failed ones
+type traceTracker struct {
+	slowest durationHeap // Min-heap: the fastest of the slowest is replaced first
+	failed  []MetricDetail
+}
+
+func (t *traceTracker) add(d MetricDetail) {
+	if d.TraceID == "" {
+		return
+	}
+	if d.IsError || d.StatusCode >= 400 {
+		if len(t.failed) < NotableTraces {
+			t.failed = append(t.failed, d)
+		}
+	}
+	if len(t.slowest) < NotableTraces {
+		heap.Push(&t.slowest, d)
+	} else if d.Duration > t.slowest[0].Duration {
+		t.slowest[0] = d
+		heap.Fix(&t.slowest, 0)
+	}
+}
+
+// slowestFirst returns a copy of the slowest requests, slowest first
+func (t *traceTracker) slowestFirst() []MetricDetail {
+	if len(t.slowest) == 0 {
+		return nil
+	}
+	out := append([]MetricDetail(nil), t.slowest...)
+	sort.Slice(out, func(i, j int) bool { return out[i].Duration > out[j].Duration })
+	return out
+}
+
+// failedInOrder returns a copy of the failed requests in recording order
+func (t *traceTracker) failedInOrder() []MetricDetail {
+	if len(t.failed) == 0 {
+		return nil
+	}
+	return append([]MetricDetail(nil), t.failed...)
+}
+
+// durationHeap is a container/heap of details ordered by service time
+type durationHeap []MetricDetail
+
+func (h durationHeap) Len() int           { return len(h) }
+func (h durationHeap) Less(i, j int) bool { return h[i].Duration < h[j].Duration }
+func (h durationHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *durationHeap) Push(x any)        { *h = append(*h, x.(MetricDetail)) }
+func (h *durationHeap) Pop() any {
+	old := *h
+	d := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return d
+}
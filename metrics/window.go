@@ -0,0 +1,100 @@
+package metrics
+
+import "time"
+
+// DefaultWindow is the width of time-series windows when none is configured.
+const DefaultWindow = time.Second
+
+// WindowResults summarises the requests that completed in one time window.
+type WindowResults struct {
+	Start       time.Time     // Window start, aligned to a multiple of the window width
+	Duration    time.Duration // Window width
+	AchievedRPS float64       // Completed requests per second over Duration
+	Results     AggregatedResults
+}
+
+// maxWindows bounds the windows a series keeps. A run that would need more
+// doubles the window width instead, merging neighbouring windows, so the
+// series stays the same size however long the run is.
+const maxWindows = 1000
+
+// windowPrecision caps the histogram precision of each window: two
+// significant digits are plenty for a chart and keep the histograms small.
+const windowPrecision = 2
+
+// windowSeries buckets details by completion time into fixed, wall-clock
+// aligned windows.
+type windowSeries struct {
+	width       time.Duration
+	precision   int
+	buckets     map[int64]*aggregator // Keyed by window start in Unix nanoseconds
+	first, last int64                 // Earliest and latest key in buckets
+}
+
+func newWindowSeries(width time.Duration, precision int) *windowSeries {
+	return &windowSeries{width: width, precision: min(precision, windowPrecision), buckets: make(map[int64]*aggregator)}
+}
+
+// add folds detail into the window containing its completion time; details
+// without a timestamp are not placed in the series.
+func (s *windowSeries) add(detail MetricDetail) {
+	if detail.Timestamp.IsZero() {
+		return
+	}
+	key := detail.Timestamp.Truncate(s.width).UnixNano()
+	if len(s.buckets) == 0 {
+		s.first, s.last = key, key
+	}
+	s.first, s.last = min(s.first, key), max(s.last, key)
+	for (s.last-s.first)/int64(s.width) >= maxWindows {
+		s.widen()
+		key = detail.Timestamp.Truncate(s.width).UnixNano()
+	}
+	agg := s.buckets[key]
+	if agg == nil {
+		agg = newLatencyAggregator(s.precision)
+		s.buckets[key] = agg
+	}
+	agg.add(detail)
+}
+
+// widen doubles the window width, merging each pair of neighbouring windows
+func (s *windowSeries) widen() {
+	s.width *= 2
+	align := func(key int64) int64 { return time.Unix(0, key).Truncate(s.width).UnixNano() }
+	merged := make(map[int64]*aggregator, len(s.buckets)/2+1)
+	for key, agg := range s.buckets {
+		wide := align(key)
+		if into := merged[wide]; into != nil {
+			into.merge(agg)
+		} else {
+			merged[wide] = agg
+		}
+	}
+	s.buckets = merged
+	s.first, s.last = align(s.first), align(s.last)
+}
+
+// results returns every window from the first to the last that saw a
+// request, in time order. Windows without requests are included with zero
+// counts so the series has no gaps.
+func (s *windowSeries) results(percentiles []float64) []WindowResults {
+	if len(s.buckets) == 0 {
+		return nil
+	}
+	out := make([]WindowResults, 0, (s.last-s.first)/int64(s.width)+1)
+	for key := s.first; key <= s.last; key += int64(s.width) {
+		agg := s.buckets[key]
+		if agg == nil {
+			agg = newLatencyAggregator(s.precision)
+		}
+		res := agg.results(percentiles)
+		out = append(out, WindowResults{
+			Start:       time.Unix(0, key),
+			Duration:    s.width,
+			AchievedRPS: float64(res.TotalRequests) / s.width.Seconds(),
+			Results:     res,
+		})
+	}
+	return out
+}
@@ -0,0 +1,282 @@
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+)
+
+// DefaultServiceName is the service.name of exported spans when none is configured
+const DefaultServiceName = "benchmarking-tool"
+
+const (
+	defaultQueueSize = 8192             // Spans waiting for export before new ones are dropped
+	batchSize        = 512              // Spans per export request at most
+	flushInterval    = time.Second      // Longest a span waits for its batch to fill
+	requestTimeout   = 10 * time.Second // Bounds one export request
+)
+
+// OTLP span kind and status codes
+const (
+	spanKindClient  = 3
+	statusCodeError = 2
+)
+
+// Exporter is a metrics.Sink that exports a client span per traced request
+// to an OTLP/HTTP collector, JSON encoded. Record never blocks: spans are
+// queued for a background goroutine and dropped when the queue is full.
+type Exporter struct {
+	endpoint string
+	headers  map[string]string
+	service  string
+	client   *http.Client
+	queue    chan metrics.MetricDetail
+	dropped  atomic.Int64
+
+	batches, failed int
+	lastErr         error
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewExporter creates an exporter for cfg.OTLPEndpoint; call Start to begin exporting.
+func NewExporter(cfg config.TracingConfig) *Exporter {
+	return newExporter(cfg, defaultQueueSize)
+}
+
+func newExporter(cfg config.TracingConfig, queueSize int) *Exporter {
+	service := cfg.ServiceName
+	if service == "" {
+		service = DefaultServiceName
+	}
+	return &Exporter{
+		endpoint: cfg.OTLPEndpoint,
+		headers:  cfg.OTLPHeaders,
+		service:  service,
+		client:   &http.Client{Timeout: requestTimeout},
+		queue:    make(chan metrics.MetricDetail, queueSize),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Record queues the span of a traced request
+func (e *Exporter) Record(d metrics.MetricDetail) {
+	if d.TraceID == "" || d.SpanID == "" {
+		return
+	}
+	select {
+	case e.queue <- d:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// Start begins exporting in the background
+func (e *Exporter) Start() {
+	go e.loop()
+}
+
+// Stop exports the queued spans and ends exporting. It returns an error when
+// spans were dropped or an export failed.
+func (e *Exporter) Stop() error {
+	close(e.stop)
+	<-e.done
+	var errs []error
+	if e.failed > 0 {
+		errs = append(errs, fmt.Errorf("%d of %d span exports failed, last error: %w", e.failed, e.batches, e.lastErr))
+	}
+	if n := e.dropped.Load(); n > 0 {
+		errs = append(errs, fmt.Errorf("%d spans dropped because the collector could not keep up", n))
+	}
+	return errors.Join(errs...)
+}
+
+func (e *Exporter) loop() {
+	defer close(e.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	batch := make([]metrics.MetricDetail, 0, batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			e.export(batch)
+			batch = batch[:0]
+		}
+	}
+	for {
+		select {
+		case d := <-e.queue:
+			if batch = append(batch, d); len(batch) == batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			for {
+				select {
+				case d := <-e.queue:
+					if batch = append(batch, d); len(batch) == batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// export sends one batch; a failed batch is counted and dropped
+func (e *Exporter) export(batch []metrics.MetricDetail) {
+	e.batches++
+	if err := e.post(batch); err != nil {
+		e.failed++
+		e.lastErr = err
+	}
+}
+
+func (e *Exporter) post(batch []metrics.MetricDetail) error {
+	body, err := json.Marshal(e.request(batch))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build span export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans to %s: %w", e.endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("collector %s returned %s: %s", e.endpoint, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// OTLP/JSON trace export request (opentelemetry-proto ExportTraceServiceRequest)
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue holds one of its fields; 64-bit integers are strings in OTLP/JSON
+type anyValue struct {
+	StringValue string `json:"stringValue,omitempty"`
+	IntValue    string `json:"intValue,omitempty"`
+}
+
+func stringAttr(key, value string) keyValue {
+	return keyValue{Key: key, Value: anyValue{StringValue: value}}
+}
+
+func intAttr(key string, value int64) keyValue {
+	return keyValue{Key: key, Value: anyValue{IntValue: strconv.FormatInt(value, 10)}}
+}
+
+// request converts a batch to the export request
+func (e *Exporter) request(batch []metrics.MetricDetail) exportRequest {
+	spans := make([]span, 0, len(batch))
+	for _, d := range batch {
+		spans = append(spans, toSpan(d))
+	}
+	return exportRequest{ResourceSpans: []resourceSpans{{
+		Resource:   resource{Attributes: []keyValue{stringAttr("service.name", e.service)}},
+		ScopeSpans: []scopeSpans{{Scope: scope{Name: DefaultServiceName}, Spans: spans}},
+	}}}
+}
+
+// toSpan describes one request as a client span, following the OpenTelemetry
+// HTTP conventions plus benchmark.* attributes
+func toSpan(d metrics.MetricDetail) span {
+	name := d.Method
+	if d.Endpoint != "" {
+		name += " " + d.Endpoint
+	}
+	s := span{
+		TraceID:           d.TraceID,
+		SpanID:            d.SpanID,
+		Name:              name,
+		Kind:              spanKindClient,
+		StartTimeUnixNano: strconv.FormatInt(d.Timestamp.Add(-d.Duration).UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(d.Timestamp.UnixNano(), 10),
+		Attributes: []keyValue{
+			stringAttr("http.request.method", d.Method),
+			stringAttr("url.full", d.URL),
+		},
+	}
+	if d.StatusCode != 0 {
+		s.Attributes = append(s.Attributes, intAttr("http.response.status_code", int64(d.StatusCode)))
+	}
+	for _, kv := range [][2]string{{"benchmark.endpoint", d.Endpoint}, {"benchmark.base_url", d.BaseURL}, {"benchmark.stage", d.Stage}} {
+		if kv[1] != "" {
+			s.Attributes = append(s.Attributes, stringAttr(kv[0], kv[1]))
+		}
+	}
+	switch {
+	case d.IsError:
+		s.Attributes = append(s.Attributes, stringAttr("error.type", "transport"))
+		s.Status = status{Code: statusCodeError, Message: d.ErrorMsg}
+	case d.StatusCode >= 400:
+		s.Attributes = append(s.Attributes, stringAttr("error.type", strconv.Itoa(d.StatusCode)))
+		s.Status = status{Code: statusCodeError}
+	}
+	return s
+}
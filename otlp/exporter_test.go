@@ -0,0 +1,142 @@
+package otlp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+)
+
+// fakeCollector decodes every export request it receives
+type fakeCollector struct {
+	mu       sync.Mutex
+	requests []exportRequest
+	headers  []http.Header
+	status   int
+	block    chan struct{} // When set, requests wait for it to close
+}
+
+func (c *fakeCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.block != nil {
+		<-c.block
+	}
+	var req exportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+	if c.status != 0 {
+		http.Error(w, "quota exceeded", c.status)
+		return
+	}
+	_, _ = w.Write([]byte("{}"))
+}
+
+func (c *fakeCollector) spans() []span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var all []span
+	for _, req := range c.requests {
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				all = append(all, ss.Spans...)
+			}
+		}
+	}
+	return all
+}
+
+func attr(s span, key string) anyValue {
+	for _, kv := range s.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return anyValue{}
+}
+
+func TestExporter_Spans(t *testing.T) {
+	collector := &fakeCollector{}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	e := NewExporter(config.TracingConfig{OTLPEndpoint: srv.URL + "/v1/traces", ServiceName: "checkout-load", OTLPHeaders: map[string]string{"X-Api-Key": "k"}})
+	e.Start()
+	end := time.Unix(1700000000, 0)
+	e.Record(metrics.MetricDetail{
+		TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331",
+		Method: "GET", URL: srv.URL + "/users/1", StatusCode: 503, Endpoint: "get_user", Stage: "peak",
+		Duration: 250 * time.Millisecond, Timestamp: end,
+	})
+	e.Record(metrics.MetricDetail{
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", SpanID: "00f067aa0ba902b7",
+		Method: "POST", IsError: true, ErrorMsg: "connection refused", Duration: time.Millisecond, Timestamp: end,
+	})
+	e.Record(metrics.MetricDetail{Method: "GET", StatusCode: 200}) // Untraced
+	if err := e.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := collector.spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if svc := collector.requests[0].ResourceSpans[0].Resource.Attributes[0]; svc.Key != "service.name" || svc.Value.StringValue != "checkout-load" {
+		t.Errorf("unexpected resource attribute %+v", svc)
+	}
+	if collector.headers[0].Get("X-Api-Key") != "k" || collector.headers[0].Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected headers %v", collector.headers[0])
+	}
+
+	s := spans[0]
+	if s.TraceID != "0af7651916cd43dd8448eb211c80319c" || s.SpanID != "b7ad6b7169203331" || s.Name != "GET get_user" || s.Kind != spanKindClient {
+		t.Errorf("unexpected span %+v", s)
+	}
+	if s.StartTimeUnixNano != "1699999999750000000" || s.EndTimeUnixNano != "1700000000000000000" {
+		t.Errorf("unexpected span times %s - %s", s.StartTimeUnixNano, s.EndTimeUnixNano)
+	}
+	if attr(s, "http.response.status_code").IntValue != "503" || attr(s, "benchmark.stage").StringValue != "peak" || s.Status.Code != statusCodeError {
+		t.Errorf("unexpected attributes %+v / status %+v", s.Attributes, s.Status)
+	}
+	if failed := spans[1]; failed.Status.Message != "connection refused" || attr(failed, "error.type").StringValue != "transport" {
+		t.Errorf("a transport error should mark the span failed, got %+v", failed)
+	}
+}
+
+func TestExporter_Failures(t *testing.T) {
+	collector := &fakeCollector{status: http.StatusTooManyRequests, block: make(chan struct{})}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	e := newExporter(config.TracingConfig{OTLPEndpoint: srv.URL}, 4)
+	e.Start()
+	recorded := make(chan struct{})
+	go func() {
+		defer close(recorded)
+		for range 100 {
+			e.Record(metrics.MetricDetail{TraceID: "0af7651916cd43dd8448eb211c80319c", SpanID: "b7ad6b7169203331", Timestamp: time.Now()})
+		}
+	}()
+	select {
+	case <-recorded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Record blocked on a slow collector")
+	}
+	close(collector.block)
+
+	err := e.Stop()
+	if err == nil || !strings.Contains(err.Error(), "span exports failed") || !strings.Contains(err.Error(), "429") ||
+		!strings.Contains(err.Error(), "spans dropped") {
+		t.Errorf("expected failed exports and dropped spans to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package prometheus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
+)
+
+// DefaultBuckets are the latency histogram upper bounds in seconds
+var DefaultBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// contentType is the Prometheus text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// seriesKey is the label set of one request series
+type seriesKey struct {
+	endpoint, method, status, baseURL string
+}
+
+// labels renders the key as a label list, optionally followed by extra pairs
+func (k seriesKey) labels(extra ...string) string {
+	pairs := []string{
+		"endpoint", k.endpoint,
+		"method", k.method,
+		"status", k.status,
+		"base_url", k.baseURL,
+	}
+	pairs = append(pairs, extra...)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], escapeLabel(pairs[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// histogram is a Prometheus histogram: per-bucket counts are cumulated on output
+type histogram struct {
+	counts []uint64 // One per DefaultBuckets entry; larger values only reach count
+	sum    float64
+	count  uint64
+}
+
+func (h *histogram) observe(seconds float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(DefaultBuckets))
+	}
+	if i := sort.SearchFloat64s(DefaultBuckets, seconds); i < len(DefaultBuckets) {
+		h.counts[i]++
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// series holds everything recorded for one label set
+type series struct {
+	requests      int64
+	bytesSent     int64
+	bytesReceived int64
+	duration      histogram
+	response      histogram
+}
+
+// Exporter is a metrics.Sink that keeps Prometheus counters and latency
+// histograms per endpoint, method, status and base URL, and serves them
+// together with the runner's live gauges in the text exposition format.
+type Exporter struct {
+	progress func() runner.Progress
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+}
+
+// NewExporter creates an exporter; progress (usually Runner.Progress) may be
+// nil to leave out the runner gauges.
+func NewExporter(progress func() runner.Progress) *Exporter {
+	return &Exporter{progress: progress, series: make(map[seriesKey]*series)}
+}
+
+// Record counts a completed request
+func (e *Exporter) Record(d metrics.MetricDetail) {
+	key := seriesKey{endpoint: d.Endpoint, method: d.Method, status: strconv.Itoa(d.StatusCode), baseURL: d.BaseURL}
+	responseTime := d.ResponseTime
+	if responseTime == 0 {
+		responseTime = d.Duration
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s := e.series[key]
+	if s == nil {
+		s = &series{}
+		e.series[key] = s
+	}
+	s.requests++
+	s.bytesSent += d.BytesSent
+	s.bytesReceived += d.BytesReceived
+	s.duration.observe(d.Duration.Seconds())
+	s.response.observe(responseTime.Seconds())
+}
+
+// ServeHTTP writes the current metrics
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_ = e.Write(w)
+}
+
+// Write renders every metric in the text exposition format
+func (e *Exporter) Write(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	e.writeSeries(bw)
+	if e.progress != nil {
+		writeProgress(bw, e.progress())
+	}
+	return bw.Flush()
+}
+
+// snapshot copies every series under the lock, in label order, so rendering
+// to a slow scraper never holds up Record
+func (e *Exporter) snapshot() ([]seriesKey, []series) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	keys := make([]seriesKey, 0, len(e.series))
+	for k := range e.series {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.endpoint != b.endpoint {
+			return a.endpoint < b.endpoint
+		}
+		if a.method != b.method {
+			return a.method < b.method
+		}
+		if a.status != b.status {
+			return a.status < b.status
+		}
+		return a.baseURL < b.baseURL
+	})
+	values := make([]series, len(keys))
+	for i, k := range keys {
+		values[i] = *e.series[k]
+		values[i].duration.counts = append([]uint64(nil), values[i].duration.counts...)
+		values[i].response.counts = append([]uint64(nil), values[i].response.counts...)
+	}
+	return keys, values
+}
+
+// writeSeries writes the per-request counters and histograms in label order
+func (e *Exporter) writeSeries(w io.Writer) {
+	keys, values := e.snapshot()
+
+	counter := func(name, help string, value func(*series) int64) {
+		writeHeader(w, name, "counter", help)
+		for i, k := range keys {
+			fmt.Fprintf(w, "%s%s %d\n", name, k.labels(), value(&values[i]))
+		}
+	}
+	hist := func(name, help string, value func(*series) *histogram) {
+		writeHeader(w, name, "histogram", help)
+		for i, k := range keys {
+			h := value(&values[i])
+			var cumulative uint64
+			for i, le := range DefaultBuckets {
+				if h.counts != nil {
+					cumulative += h.counts[i]
+				}
+				fmt.Fprintf(w, "%s_bucket%s %d\n", name, k.labels("le", formatFloat(le)), cumulative)
+			}
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, k.labels("le", "+Inf"), h.count)
+			fmt.Fprintf(w, "%s_sum%s %s\n", name, k.labels(), formatFloat(h.sum))
+			fmt.Fprintf(w, "%s_count%s %d\n", name, k.labels(), h.count)
+		}
+	}
+
+	counter("benchmark_requests_total", "Completed requests.", func(s *series) int64 { return s.requests })
+	counter("benchmark_request_bytes_total", "Request body bytes sent.", func(s *series) int64 { return s.bytesSent })
+	counter("benchmark_response_bytes_total", "Response body bytes received.", func(s *series) int64 { return s.bytesReceived })
+	hist("benchmark_request_duration_seconds", "Service time: from sending the request to reading the whole response.",
+		func(s *series) *histogram { return &s.duration })
+	hist("benchmark_response_time_seconds", "Response time corrected for coordinated omission: from the intended start to completion.",
+		func(s *series) *histogram { return &s.response })
+}
+
+// writeProgress writes the runner's live gauges
+func writeProgress(w io.Writer, p runner.Progress) {
+	gauge := func(name, typ, help string, v float64) {
+		writeHeader(w, name, typ, help)
+		fmt.Fprintf(w, "%s %s\n", name, formatFloat(v))
+	}
+	gauge("benchmark_elapsed_seconds", "gauge", "Time since the benchmark started.", p.Elapsed.Seconds())
+	gauge("benchmark_target_rps", "gauge", "Scheduler rate in open-loop modes.", p.TargetRPS)
+	gauge("benchmark_virtual_users", "gauge", "Active virtual users in closed-loop modes.", float64(p.Users))
+	gauge("benchmark_in_flight_requests", "gauge", "Requests being sent.", float64(p.InFlight))
+	gauge("benchmark_queue_depth", "gauge", "Scheduled requests waiting for a worker.", float64(p.QueueDepth))
+	gauge("benchmark_queue_capacity", "gauge", "Size of the worker queue.", float64(p.QueueCap))
+	gauge("benchmark_dropped_requests_total", "counter", "Scheduled requests dropped because the queue was full.", float64(p.Dropped))
+}
+
+func writeHeader(w io.Writer, name, typ, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// escapeLabel escapes a label value for the text format
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// writeTimeout bounds one scrape, so a stalled scraper cannot hold a connection forever
+const writeTimeout = 10 * time.Second
+
+// Server serves an Exporter on /metrics
+type Server struct {
+	srv *http.Server
+	ln  net.Listener
+}
+
+// Listen starts serving e on addr (e.g. ":9464") in the background
+func Listen(addr string, e *Exporter) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start Prometheus endpoint: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	s := &Server{srv: &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second, WriteTimeout: writeTimeout}, ln: ln}
+	go func() { _ = s.srv.Serve(ln) }()
+	return s, nil
+}
+
+// Addr is the address the server listens on
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close stops the server, letting in-flight scrapes finish for up to a second
+func (s *Server) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
@@ -0,0 +1,136 @@
+package prometheus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
+)
+
+func TestExporter_Write(t *testing.T) {
+	e := NewExporter(func() runner.Progress {
+		return runner.Progress{Elapsed: 90 * time.Second, TargetRPS: 50, InFlight: 3, QueueDepth: 2, QueueCap: 100, Dropped: 4}
+	})
+	for _, ms := range []int{2, 20, 200} {
+		e.Record(metrics.MetricDetail{
+			Endpoint: "get_user", Method: "GET", StatusCode: 200, BaseURL: "http://a",
+			Duration: time.Duration(ms) * time.Millisecond, ResponseTime: time.Duration(ms+1) * time.Millisecond,
+			BytesSent: 10, BytesReceived: 100,
+		})
+	}
+	e.Record(metrics.MetricDetail{Endpoint: `say "hi"`, Method: "POST", StatusCode: 500, Duration: 20 * time.Second})
+
+	var buf bytes.Buffer
+	if err := e.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	labels := `endpoint="get_user",method="GET",status="200",base_url="http://a"`
+	for _, want := range []string{
+		"# TYPE benchmark_requests_total counter\n",
+		"benchmark_requests_total{" + labels + "} 3\n",
+		"benchmark_request_bytes_total{" + labels + "} 30\n",
+		"benchmark_response_bytes_total{" + labels + "} 300\n",
+		"# TYPE benchmark_request_duration_seconds histogram\n",
+		"benchmark_request_duration_seconds_bucket{" + labels + `,le="0.001"} 0` + "\n",
+		"benchmark_request_duration_seconds_bucket{" + labels + `,le="0.0025"} 1` + "\n",
+		"benchmark_request_duration_seconds_bucket{" + labels + `,le="0.025"} 2` + "\n",
+		"benchmark_request_duration_seconds_bucket{" + labels + `,le="0.25"} 3` + "\n",
+		"benchmark_request_duration_seconds_bucket{" + labels + `,le="+Inf"} 3` + "\n",
+		"benchmark_request_duration_seconds_count{" + labels + "} 3\n",
+		"benchmark_response_time_seconds_sum{" + labels + "} 0.225\n",
+		`benchmark_request_duration_seconds_bucket{endpoint="say \"hi\"",method="POST",status="500",base_url="",le="10"} 0` + "\n",
+		`benchmark_request_duration_seconds_bucket{endpoint="say \"hi\"",method="POST",status="500",base_url="",le="+Inf"} 1` + "\n",
+		"benchmark_elapsed_seconds 90\n",
+		"benchmark_target_rps 50\n",
+		"benchmark_in_flight_requests 3\n",
+		"benchmark_queue_depth 2\n",
+		"benchmark_queue_capacity 100\n",
+		"# TYPE benchmark_dropped_requests_total counter\nbenchmark_dropped_requests_total 4\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, `endpoint="get_user"`) > strings.Index(out, `endpoint="say`) {
+		t.Error("series should be sorted by endpoint")
+	}
+}
+
+// stalledWriter blocks every write until release is closed
+type stalledWriter struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (w *stalledWriter) Write(p []byte) (int, error) {
+	select {
+	case w.entered <- struct{}{}:
+	default:
+	}
+	<-w.release
+	return len(p), nil
+}
+
+func TestExporter_StalledScraperDoesNotBlockRecord(t *testing.T) {
+	e := NewExporter(nil)
+	for i := range 50 { // More output than the write buffer holds
+		e.Record(metrics.MetricDetail{Endpoint: fmt.Sprintf("endpoint_%d", i), Method: "GET", StatusCode: 200, Duration: time.Millisecond})
+	}
+	w := &stalledWriter{entered: make(chan struct{}, 1), release: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = e.Write(w)
+	}()
+	<-w.entered
+
+	recorded := make(chan struct{})
+	go func() {
+		defer close(recorded)
+		e.Record(metrics.MetricDetail{Endpoint: "endpoint_0", Method: "GET", StatusCode: 200, Duration: time.Millisecond})
+	}()
+	select {
+	case <-recorded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Record blocked while a scrape was being written")
+	}
+	close(w.release)
+	<-done
+}
+
+func TestListen(t *testing.T) {
+	e := NewExporter(nil)
+	e.Record(metrics.MetricDetail{Endpoint: "health", Method: "GET", StatusCode: 200, Duration: time.Millisecond})
+	srv, err := Listen("127.0.0.1:0", e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+
+	resp, err := http.Get("http://" + srv.Addr() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); ct != contentType {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if !strings.Contains(string(body), `benchmark_requests_total{endpoint="health",method="GET",status="200",base_url=""} 1`) {
+		t.Errorf("unexpected body:\n%s", body)
+	}
+	if strings.Contains(string(body), "benchmark_elapsed_seconds") {
+		t.Error("runner gauges need a progress function")
+	}
+
+	if _, err := Listen(srv.Addr(), e); err == nil {
+		t.Error("listening on a busy address should fail")
+	}
+}
@@ -0,0 +1,191 @@
+package reporter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Chart geometry for the HTML report. Charts are plain SVG computed here so
+// the report is a single file that renders offline without scripts.
+const (
+	chartWidth  = 760
+	chartHeight = 260
+	chartLeft   = 64 // Room for y-axis labels
+	chartRight  = 16
+	chartTop    = 16
+	chartBottom = 36 // Room for x-axis labels
+)
+
+// chartPalette colours series in order
+var chartPalette = []string{"#2563eb", "#dc2626", "#16a34a", "#9333ea", "#ea580c", "#0891b2"}
+
+// axisTick is one labelled gridline
+type axisTick struct {
+	Pos   float64 // Pixel position along the axis
+	Label string
+}
+
+// lineSeries is one polyline of a line chart
+type lineSeries struct {
+	Name   string
+	Color  string
+	Points string // SVG polyline points
+}
+
+// lineChart plots series against elapsed seconds
+type lineChart struct {
+	Title  string
+	Unit   string
+	Width  int
+	Height int
+	Left   float64
+	Right  float64 // x of the plot's right edge
+	Top    float64
+	Bottom float64 // y of the plot's bottom edge
+	XTicks []axisTick
+	YTicks []axisTick
+	Series []lineSeries
+}
+
+// newLineChart scales the series (one y per x) into the plot area; x is in seconds
+func newLineChart(title, unit string, xs []float64, names []string, ys [][]float64) *lineChart {
+	c := &lineChart{
+		Title: title, Unit: unit, Width: chartWidth, Height: chartHeight,
+		Left: chartLeft, Right: chartWidth - chartRight, Top: chartTop, Bottom: chartHeight - chartBottom,
+	}
+	if len(xs) == 0 {
+		return c
+	}
+	xMax := max(xs[len(xs)-1], 1)
+	yMax := 0.0
+	for _, s := range ys {
+		for _, y := range s {
+			yMax = max(yMax, y)
+		}
+	}
+	yMax = niceCeil(yMax)
+
+	xPos := func(x float64) float64 { return c.Left + x/xMax*(c.Right-c.Left) }
+	yPos := func(y float64) float64 { return c.Bottom - y/yMax*(c.Bottom-c.Top) }
+	for i, name := range names {
+		var pts strings.Builder
+		for j, y := range ys[i] {
+			fmt.Fprintf(&pts, "%.1f,%.1f ", xPos(xs[j]), yPos(y))
+		}
+		c.Series = append(c.Series, lineSeries{Name: name, Color: chartPalette[i%len(chartPalette)], Points: strings.TrimSpace(pts.String())})
+	}
+	for _, v := range ticks(yMax) {
+		c.YTicks = append(c.YTicks, axisTick{Pos: round1(yPos(v)), Label: formatTick(v)})
+	}
+	for _, v := range ticks(niceCeil(xMax)) {
+		if v <= xMax {
+			c.XTicks = append(c.XTicks, axisTick{Pos: round1(xPos(v)), Label: formatTick(v) + "s"})
+		}
+	}
+	return c
+}
+
+// barRect is one bar of a bar chart
+type barRect struct {
+	X, Y, W, H float64
+	Color      string
+	Title      string // Tooltip
+}
+
+// barChart draws one group of bars per category, one bar per series
+type barChart struct {
+	Title  string
+	Unit   string
+	Width  int
+	Height int
+	Left   float64
+	Right  float64
+	Top    float64
+	Bottom float64
+	YTicks []axisTick
+	XTicks []axisTick // Category labels, centred under each group
+	Bars   []barRect
+	Legend []lineSeries // Name and colour only
+}
+
+// newBarChart lays out values[series][category] as grouped bars
+func newBarChart(title, unit string, categories, names []string, values [][]float64) *barChart {
+	c := &barChart{
+		Title: title, Unit: unit, Width: chartWidth, Height: chartHeight,
+		Left: chartLeft, Right: chartWidth - chartRight, Top: chartTop, Bottom: chartHeight - chartBottom,
+	}
+	if len(categories) == 0 || len(names) == 0 {
+		return c
+	}
+	yMax := 0.0
+	for _, s := range values {
+		for _, v := range s {
+			yMax = max(yMax, v)
+		}
+	}
+	yMax = niceCeil(yMax)
+	yPos := func(y float64) float64 { return c.Bottom - y/yMax*(c.Bottom-c.Top) }
+
+	group := (c.Right - c.Left) / float64(len(categories))
+	barW := group * 0.8 / float64(len(names))
+	for i, cat := range categories {
+		x0 := c.Left + float64(i)*group + group*0.1
+		c.XTicks = append(c.XTicks, axisTick{Pos: round1(c.Left + (float64(i)+0.5)*group), Label: cat})
+		for j, name := range names {
+			v := values[j][i]
+			title := fmt.Sprintf("%s %s: %s%s", cat, name, formatTick(v), unit)
+			if len(names) == 1 {
+				title = fmt.Sprintf("%s: %s%s", cat, formatTick(v), unit)
+			}
+			c.Bars = append(c.Bars, barRect{
+				X: round1(x0 + float64(j)*barW), Y: round1(yPos(v)), W: round1(barW * 0.92), H: round1(c.Bottom - yPos(v)),
+				Color: chartPalette[j%len(chartPalette)],
+				Title: title,
+			})
+		}
+	}
+	for j, name := range names {
+		c.Legend = append(c.Legend, lineSeries{Name: name, Color: chartPalette[j%len(chartPalette)]})
+	}
+	for _, v := range ticks(yMax) {
+		c.YTicks = append(c.YTicks, axisTick{Pos: round1(yPos(v)), Label: formatTick(v)})
+	}
+	return c
+}
+
+// niceCeil rounds v up to 1, 2 or 5 times a power of ten (minimum 1)
+func niceCeil(v float64) float64 {
+	if v <= 1 {
+		return 1
+	}
+	mag := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if v <= m*mag {
+			return m * mag
+		}
+	}
+	return 10 * mag
+}
+
+// ticks returns five evenly spaced values from 0 to top inclusive
+func ticks(top float64) []float64 {
+	out := make([]float64, 0, 5)
+	for i := range 5 {
+		out = append(out, top*float64(i)/4)
+	}
+	return out
+}
+
+// round1 rounds a pixel coordinate to one decimal place
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// formatTick prints an axis value without trailing zeros
+func formatTick(v float64) string {
+	if v >= 100 || v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
@@ -0,0 +1,165 @@
+package reporter
+
+import (
+	"benchmarking-tool/metrics"
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sampleCSVHeader names the columns written by SampleCSVWriter
+var sampleCSVHeader = []string{
+	"timestamp", "stage", "endpoint", "base_url", "url", "method", "status",
+	"duration_ms", "response_time_ms", "dns_ms", "connect_ms", "tls_ms", "ttfb_ms", "download_ms",
+	"bytes_sent", "bytes_received", "error", "error_message",
+}
+
+// SampleCSVWriter is a metrics.Sink that streams one CSV row per request to
+// a file as the run progresses, so nothing is held in memory. The first
+// write error is kept and returned by Close; later rows are dropped.
+type SampleCSVWriter struct {
+	mu   sync.Mutex
+	file *os.File
+	buf  *bufio.Writer
+	csv  *csv.Writer
+	err  error
+}
+
+// NewSampleCSVWriter creates path and writes the header row
+func NewSampleCSVWriter(path string) (*SampleCSVWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create samples CSV: %w", err)
+	}
+	buf := bufio.NewWriterSize(f, 64<<10)
+	w := &SampleCSVWriter{file: f, buf: buf, csv: csv.NewWriter(buf)}
+	w.err = w.csv.Write(sampleCSVHeader)
+	return w, nil
+}
+
+// Record appends the detail as a CSV row
+func (w *SampleCSVWriter) Record(d metrics.MetricDetail) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil {
+		return
+	}
+	responseTime := d.ResponseTime
+	if responseTime == 0 {
+		responseTime = d.Duration
+	}
+	w.err = w.csv.Write([]string{
+		d.Timestamp.UTC().Format(time.RFC3339Nano),
+		d.Stage,
+		d.Endpoint,
+		d.BaseURL,
+		d.URL,
+		d.Method,
+		strconv.Itoa(d.StatusCode),
+		formatMillis(d.Duration),
+		formatMillis(responseTime),
+		formatMillis(d.Timings.DNSLookup),
+		formatMillis(d.Timings.TCPConnect),
+		formatMillis(d.Timings.TLSHandshake),
+		formatMillis(d.Timings.TimeToFirstByte),
+		formatMillis(d.Timings.Download),
+		strconv.FormatInt(d.BytesSent, 10),
+		strconv.FormatInt(d.BytesReceived, 10),
+		strconv.FormatBool(d.IsError || d.StatusCode >= 400),
+		d.ErrorMsg,
+	})
+}
+
+// Close flushes buffered rows and closes the file
+func (w *SampleCSVWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.csv.Flush()
+	if w.err == nil {
+		w.err = w.csv.Error()
+	}
+	if err := w.buf.Flush(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if err := w.file.Close(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if w.err != nil {
+		return fmt.Errorf("failed to write samples CSV: %w", w.err)
+	}
+	return nil
+}
+
+// WriteSummaryCSV writes one row of aggregates for the whole run, then one
+// per stage, endpoint and base URL (the scope column tells them apart).
+// Percentile columns follow the configured percentiles.
+func WriteSummaryCSV(path string, results metrics.AggregatedResults) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create summary CSV: %w", err)
+	}
+	w := csv.NewWriter(f)
+
+	header := []string{"scope", "name", "requests", "successful", "failed", "error_rate", "achieved_rps",
+		"min_ms", "avg_ms", "max_ms"}
+	for _, pv := range results.Percentiles {
+		header = append(header, metrics.PercentileLabel(pv.Percentile)+"_ms")
+	}
+	header = append(header, "response_avg_ms", "response_max_ms", "bytes_sent", "bytes_received")
+	_ = w.Write(header)
+
+	row := func(scope, name string, res metrics.AggregatedResults, rps float64) {
+		errorRate := 0.0
+		if res.TotalRequests > 0 {
+			errorRate = float64(res.FailedRequests) / float64(res.TotalRequests)
+		}
+		rec := []string{
+			scope, name,
+			strconv.FormatInt(res.TotalRequests, 10),
+			strconv.FormatInt(res.SuccessfulRequests, 10),
+			strconv.FormatInt(res.FailedRequests, 10),
+			strconv.FormatFloat(errorRate, 'f', 6, 64),
+			strconv.FormatFloat(rps, 'f', 3, 64),
+			formatMillis(res.MinDuration),
+			formatMillis(res.AvgDuration),
+			formatMillis(res.MaxDuration),
+		}
+		for _, pv := range results.Percentiles {
+			rec = append(rec, formatMillis(res.Percentile(pv.Percentile)))
+		}
+		rec = append(rec,
+			formatMillis(res.AvgResponseTime),
+			formatMillis(res.MaxResponseTime),
+			strconv.FormatInt(res.BytesSent, 10),
+			strconv.FormatInt(res.BytesReceived, 10),
+		)
+		_ = w.Write(rec)
+	}
+
+	row("total", "", results, results.AchievedRPS)
+	for _, st := range results.Stages {
+		row("stage", st.Name, st.Results, st.AchievedRPS)
+	}
+	for _, name := range sortedKeys(results.Endpoints) {
+		row("endpoint", name, results.Endpoints[name], results.Endpoints[name].AchievedRPS)
+	}
+	for _, name := range sortedKeys(results.BaseURLs) {
+		row("base_url", name, results.BaseURLs[name], results.BaseURLs[name].AchievedRPS)
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write summary CSV: %w", err)
+	}
+	return f.Close()
+}
+
+// formatMillis renders a duration as fractional milliseconds
+func formatMillis(d time.Duration) string {
+	return strconv.FormatFloat(millis(d), 'f', 3, 64)
+}
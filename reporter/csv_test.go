@@ -0,0 +1,107 @@
+package reporter
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"benchmarking-tool/metrics"
+)
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	return rows
+}
+
+func TestSampleCSVWriter_StreamsCollectorDetails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.csv")
+	w, err := NewSampleCSVWriter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector := metrics.NewCollector()
+	collector.AddSink(w)
+
+	ts := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	collector.AppendDetail(metrics.MetricDetail{
+		Timestamp: ts, Endpoint: "get_user", BaseURL: "http://api", URL: "http://api/users/1", Method: "GET",
+		StatusCode: 200, Duration: 12500 * time.Microsecond, ResponseTime: 20 * time.Millisecond, BytesReceived: 321,
+		Timings: metrics.RequestTimings{TimeToFirstByte: 10 * time.Millisecond},
+	})
+	collector.AppendDetail(metrics.MetricDetail{
+		Timestamp: ts, Endpoint: "create_user", URL: "http://api/users", Method: "POST",
+		Duration: time.Second, IsError: true, ErrorMsg: `dial tcp: "refused", retry`,
+	})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := readCSV(t, path)
+	if len(rows) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d rows", len(rows))
+	}
+	col := make(map[string]int)
+	for i, name := range rows[0] {
+		col[name] = i
+	}
+	first := rows[1]
+	checks := map[string]string{
+		"timestamp": "2025-03-04T05:06:07Z", "endpoint": "get_user", "status": "200",
+		"duration_ms": "12.500", "response_time_ms": "20.000", "ttfb_ms": "10.000", "bytes_received": "321", "error": "false",
+	}
+	for name, want := range checks {
+		if got := first[col[name]]; got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	second := rows[2]
+	if second[col["error"]] != "true" || second[col["error_message"]] != `dial tcp: "refused", retry` {
+		t.Errorf("Error row not escaped correctly: %v", second)
+	}
+	if second[col["response_time_ms"]] != "1000.000" {
+		t.Errorf("Response time should fall back to duration, got %s", second[col["response_time_ms"]])
+	}
+}
+
+func TestWriteSummaryCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.csv")
+	if err := WriteSummaryCSV(path, jsonTestResults()); err != nil {
+		t.Fatal(err)
+	}
+
+	rows := readCSV(t, path)
+	header := rows[0]
+	if header[0] != "scope" || header[10] != "p50_ms" || header[13] != "p99_ms" {
+		t.Fatalf("Unexpected header: %v", header)
+	}
+	var scopes []string
+	for _, row := range rows[1:] {
+		if len(row) != len(header) {
+			t.Fatalf("Row %v has %d columns, header has %d", row, len(row), len(header))
+		}
+		scopes = append(scopes, row[0]+":"+row[1])
+	}
+	want := []string{"total:", "stage:warmup", "endpoint:create_user", "endpoint:get_user", "base_url:http://api"}
+	if len(scopes) != len(want) {
+		t.Fatalf("Expected rows %v, got %v", want, scopes)
+	}
+	for i := range want {
+		if scopes[i] != want[i] {
+			t.Errorf("Row %d = %s, want %s", i, scopes[i], want[i])
+		}
+	}
+	if total := rows[1]; total[2] != "11" || total[4] != "1" {
+		t.Errorf("Unexpected total row: %v", total)
+	}
+}
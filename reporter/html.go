@@ -0,0 +1,177 @@
+package reporter
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"benchmarking-tool/metrics"
+)
+
+//go:embed templates/report.html
+var htmlReportTemplate string
+
+// htmlTemplate is parsed once; the report is the only template in the file
+var htmlTemplate = template.Must(template.New("report").Funcs(reportFuncs).Funcs(template.FuncMap{
+	"groupTable": func(label string, labels []string, rows []htmlGroupRow) htmlGroupTable {
+		return htmlGroupTable{Label: label, Labels: labels, Rows: rows}
+	},
+}).Parse(htmlReportTemplate))
+
+// htmlGroupTable is the data of one results table: a row per scope
+type htmlGroupTable struct {
+	Label  string   // First column heading
+	Labels []string // Percentile columns
+	Rows   []htmlGroupRow
+}
+
+// htmlGroupRow is one row of a results table
+type htmlGroupRow struct {
+	Name    string
+	Summary ResultSummary
+}
+
+// htmlView is what the HTML template renders
+type htmlView struct {
+	Report           *Report
+	Title            string
+	PercentileLabels []string
+	Total            []htmlGroupRow
+	Endpoints        []htmlGroupRow
+	BaseURLs         []htmlGroupRow
+	Stages           []htmlGroupRow
+	LatencyChart     *lineChart
+	ThroughputChart  *lineChart
+	PercentileChart  *barChart
+	StatusChart      *barChart
+	EndpointChart    *barChart
+}
+
+// WriteHTML renders the report as a single self-contained HTML page with
+// inline SVG charts, so it can be shared and opened offline.
+func WriteHTML(w io.Writer, rep *Report) error {
+	return htmlTemplate.Execute(w, newHTMLView(rep))
+}
+
+// WriteHTMLFile writes the HTML report to path
+func WriteHTMLFile(path string, rep *Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create HTML report: %w", err)
+	}
+	if err := WriteHTML(f, rep); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+	return f.Close()
+}
+
+// newHTMLView derives the tables and charts from the report
+func newHTMLView(rep *Report) htmlView {
+	v := htmlView{
+		Report: rep,
+		Title:  fmt.Sprintf("Benchmark report: %s mode", rep.Config.Mode),
+	}
+	for _, p := range rep.Config.Percentiles {
+		v.PercentileLabels = append(v.PercentileLabels, metrics.PercentileLabel(p))
+	}
+	v.Total = []htmlGroupRow{{Name: "all requests", Summary: rep.Results}}
+	for _, st := range rep.Stages {
+		v.Stages = append(v.Stages, htmlGroupRow{Name: st.Name, Summary: st.Results})
+	}
+	for _, name := range sortedKeys(rep.Endpoints) {
+		v.Endpoints = append(v.Endpoints, htmlGroupRow{Name: name, Summary: rep.Endpoints[name]})
+	}
+	for _, name := range sortedKeys(rep.BaseURLs) {
+		v.BaseURLs = append(v.BaseURLs, htmlGroupRow{Name: name, Summary: rep.BaseURLs[name]})
+	}
+
+	if len(rep.Windows) > 0 {
+		origin := rep.Windows[0].Start
+		xs := make([]float64, len(rep.Windows))
+		latency := make([][]float64, len(v.PercentileLabels)+1)
+		throughput := make([][]float64, 2)
+		for i, w := range rep.Windows {
+			xs[i] = w.Start.Sub(origin).Seconds()
+			latency[0] = append(latency[0], w.AvgMs)
+			for j, label := range v.PercentileLabels {
+				latency[j+1] = append(latency[j+1], w.Percentiles[label])
+			}
+			errorsPerSecond := 0.0
+			if w.DurationSeconds > 0 {
+				errorsPerSecond = float64(w.Failed) / w.DurationSeconds
+			}
+			throughput[0] = append(throughput[0], w.AchievedRPS)
+			throughput[1] = append(throughput[1], errorsPerSecond)
+		}
+		v.LatencyChart = newLineChart("Latency over time", "ms", xs, append([]string{"avg"}, v.PercentileLabels...), latency)
+		v.ThroughputChart = newLineChart("Throughput over time", "req/s", xs, []string{"achieved RPS", "errors/s"}, throughput)
+	}
+
+	if len(v.PercentileLabels) > 0 && rep.Results.Requests > 0 {
+		values := [][]float64{{}, {}}
+		for _, label := range v.PercentileLabels {
+			values[0] = append(values[0], rep.Results.Latency.Percentiles[label])
+			values[1] = append(values[1], rep.Results.ResponseTime.Percentiles[label])
+		}
+		v.PercentileChart = newBarChart("Latency percentiles", " ms", v.PercentileLabels,
+			[]string{"service time", "response time"}, values)
+	}
+
+	if len(rep.Results.StatusCodes) > 0 {
+		codes := make([]string, 0, len(rep.Results.StatusCodes))
+		for code := range rep.Results.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Slice(codes, func(i, j int) bool {
+			a, _ := strconv.Atoi(codes[i])
+			b, _ := strconv.Atoi(codes[j])
+			return a < b
+		})
+		counts := make([]float64, len(codes))
+		labels := make([]string, len(codes))
+		for i, code := range codes {
+			counts[i] = float64(rep.Results.StatusCodes[code])
+			labels[i] = code
+			if code == "0" {
+				labels[i] = "no response"
+			}
+		}
+		v.StatusChart = newBarChart("Status codes", " requests", labels, []string{"requests"}, [][]float64{counts})
+	}
+
+	if len(v.Endpoints) > 1 && len(v.PercentileLabels) > 0 {
+		top := v.PercentileLabels[len(v.PercentileLabels)-1] // Highest configured percentile
+		names := make([]string, len(v.Endpoints))
+		values := [][]float64{{}, {}}
+		for i, row := range v.Endpoints {
+			names[i] = row.Name
+			values[0] = append(values[0], row.Summary.Latency.AvgMs)
+			values[1] = append(values[1], row.Summary.Latency.Percentiles[top])
+		}
+		v.EndpointChart = newBarChart("Latency by endpoint", " ms", names, []string{"avg", top}, values)
+	}
+	return v
+}
+
+// formatMs renders milliseconds with a precision that suits the magnitude
+func formatMs(ms float64) string {
+	return time.Duration(ms * float64(time.Millisecond)).Round(durationRounding(ms)).String()
+}
+
+// durationRounding keeps about three significant digits
+func durationRounding(ms float64) time.Duration {
+	switch {
+	case ms >= 1000:
+		return time.Millisecond
+	case ms >= 1:
+		return 10 * time.Microsecond
+	default:
+		return time.Microsecond / 10
+	}
+}
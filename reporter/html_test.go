@@ -0,0 +1,83 @@
+package reporter
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+)
+
+func TestWriteHTML(t *testing.T) {
+	collector := metrics.NewCollector()
+	base := time.Now().Truncate(time.Second)
+	for i := range 30 {
+		endpoint := []string{"get_user", "create_user"}[i%2]
+		status := 200
+		if i%10 == 0 {
+			status = 503
+		}
+		collector.AppendDetail(metrics.MetricDetail{
+			Endpoint: endpoint, BaseURL: "http://api", StatusCode: status,
+			Duration: time.Duration(10+i) * time.Millisecond, Timestamp: base.Add(time.Duration(i) * 100 * time.Millisecond),
+		})
+	}
+	collector.AppendDetail(metrics.MetricDetail{Endpoint: "get_user", StatusCode: 0, IsError: true, ErrorMsg: "<script>alert(1)</script>", Timestamp: base})
+	cfg := &config.Config{Execution: config.ExecutionConfig{Mode: "fixed", RequestsPerSecond: 10}}
+	rep := NewReport(cfg, collector.GetResults(), nil, RunInfo{ConfigFile: "bench.yml", StartedAt: base, FinishedAt: base.Add(3 * time.Second)})
+
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, s := range []string{
+		"<!DOCTYPE html>", "Benchmark report: fixed mode", "bench.yml",
+		"Latency over time", "Throughput over time", "Latency percentiles", "Status codes", "Latency by endpoint",
+		"<polyline", "<rect", "get_user", "create_user", "no response", "503",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("HTML report should contain %q", s)
+		}
+	}
+	if strings.Contains(out, "<script>") || strings.Contains(out, "ZgotmplZ") {
+		t.Error("HTML report must escape content and not include scripts")
+	}
+	if strings.Contains(out, "http://") && strings.Contains(out, "<link") {
+		t.Error("HTML report should not load external resources")
+	}
+}
+
+func TestWriteHTMLFile_Search(t *testing.T) {
+	rep := &Report{
+		Config:  ConfigSummary{Mode: "search"},
+		Results: ResultSummary{Requests: 0},
+		Search:  &SearchSummary{Strategy: "bisect", Steps: []SearchStepSummary{{Stage: "search-1", TargetRPS: 50, Reason: "no requests completed"}}},
+	}
+	path := filepath.Join(t.TempDir(), "report.html")
+	if err := WriteHTMLFile(path, rep); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewLineChart_Scaling(t *testing.T) {
+	c := newLineChart("t", "ms", []float64{0, 5, 10}, []string{"a"}, [][]float64{{0, 40, 80}})
+	if len(c.Series) != 1 || c.Series[0].Points != "64.0,224.0 404.0,140.8 744.0,57.6" {
+		t.Fatalf("Unexpected points %q", c.Series[0].Points)
+	}
+	if c.YTicks[len(c.YTicks)-1].Label != "100" {
+		t.Errorf("Expected the y axis to round up to 100, got %s", c.YTicks[len(c.YTicks)-1].Label)
+	}
+}
+
+func TestNiceCeil(t *testing.T) {
+	for v, want := range map[float64]float64{0: 1, 0.3: 1, 3: 5, 12: 20, 80: 100, 101: 200, 4999: 5000} {
+		if got := niceCeil(v); got != want {
+			t.Errorf("niceCeil(%v) = %v, want %v", v, got, want)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package reporter
+
+import (
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
+	"benchmarking-tool/thresholds"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"time"
+)
+
+// ReportFormatVersion is bumped when the JSON report changes incompatibly.
+const ReportFormatVersion = 1
+
+// RunInfo describes a benchmark run for report metadata
+type RunInfo struct {
+	ConfigFile string
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// Report is the machine-readable form of a benchmark run. Durations are in
+// milliseconds and byte counts in bytes; field names are stable across
+// releases with the same FormatVersion.
+type Report struct {
+	Metadata        ReportMetadata           `json:"metadata"`
+	Config          ConfigSummary            `json:"config"`
+	Results         ResultSummary            `json:"results"`
+	DroppedRequests int64                    `json:"droppedRequests"`
+	Endpoints       map[string]ResultSummary `json:"endpoints,omitempty"`
+	BaseURLs        map[string]ResultSummary `json:"baseUrls,omitempty"`
+	Stages          []StageSummary           `json:"stages,omitempty"`
+	Windows         []WindowSummary          `json:"windows,omitempty"`
+	Timings         []TimingSummary          `json:"timings,omitempty"`
+	Search          *SearchSummary           `json:"search,omitempty"`
+	Thresholds      []thresholds.Result      `json:"thresholds,omitempty"`
+	Samples         []SampleSummary          `json:"samples,omitempty"` // Only with metrics.sampleSize
+	Traces          *TraceSummary            `json:"traces,omitempty"`
+}
+
+// ReportMetadata identifies the run and the machine it ran on
+type ReportMetadata struct {
+	FormatVersion   int       `json:"formatVersion"`
+	Tool            string    `json:"tool"`
+	ConfigFile      string    `json:"configFile,omitempty"`
+	StartedAt       time.Time `json:"startedAt"`
+	FinishedAt      time.Time `json:"finishedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	Hostname        string    `json:"hostname,omitempty"`
+	GoVersion       string    `json:"goVersion"`
+}
+
+// ConfigSummary is the load profile that produced the results
+type ConfigSummary struct {
+	Mode              string               `json:"mode"`
+	DurationSeconds   int                  `json:"durationSeconds"`
+	RequestTimeoutMs  int                  `json:"requestTimeoutMs"`
+	RequestsPerSecond int                  `json:"requestsPerSecond,omitempty"`
+	MaxWorkers        int                  `json:"maxWorkers"`
+	Arrival           string               `json:"arrival"`
+	VirtualUsers      int                  `json:"virtualUsers,omitempty"`
+	ThinkTimeMs       int                  `json:"thinkTimeMs,omitempty"`
+	Stages            []config.StageConfig `json:"stages,omitempty"`
+	Search            *config.SearchConfig `json:"search,omitempty"`
+	BaseURLs          []string             `json:"baseUrls"`
+	Endpoints         map[string]string    `json:"endpoints"` // Name to "METHOD /path"
+	Selection         string               `json:"endpointSelection"`
+	Weights           map[string]float64   `json:"weights,omitempty"`
+	Percentiles       []float64            `json:"percentiles"`
+}
+
+// LatencySummary describes one latency distribution in milliseconds.
+// Percentiles are keyed by label ("p50", "p99.9").
+type LatencySummary struct {
+	MinMs       float64            `json:"minMs"`
+	AvgMs       float64            `json:"avgMs"`
+	MaxMs       float64            `json:"maxMs"`
+	Percentiles map[string]float64 `json:"percentiles"`
+}
+
+// ResultSummary is metrics.AggregatedResults in report units
+type ResultSummary struct {
+	Requests          int64            `json:"requests"`
+	Successful        int64            `json:"successful"`
+	Failed            int64            `json:"failed"`
+	ErrorRate         float64          `json:"errorRate"` // Failed fraction, 0..1
+	ElapsedSeconds    float64          `json:"elapsedSeconds"`
+	AchievedRPS       float64          `json:"achievedRps"`
+	Latency           LatencySummary   `json:"latency"`      // Service time
+	ResponseTime      LatencySummary   `json:"responseTime"` // Coordinated-omission-corrected
+	BytesSent         int64            `json:"bytesSent"`
+	BytesReceived     int64            `json:"bytesReceived"`
+	SentPerSecond     float64          `json:"sentBytesPerSecond"`
+	ReceivedPerSecond float64          `json:"receivedBytesPerSecond"`
+	StatusCodes       map[string]int64 `json:"statusCodes"`
+	Errors            map[string]int   `json:"errors,omitempty"`
+}
+
+// StageSummary is one load stage of a staged run
+type StageSummary struct {
+	Name            string        `json:"name"`
+	DurationSeconds float64       `json:"durationSeconds"`
+	AchievedRPS     float64       `json:"achievedRps"`
+	Results         ResultSummary `json:"results"`
+}
+
+// WindowSummary is one time-series window
+type WindowSummary struct {
+	Start           time.Time          `json:"start"`
+	DurationSeconds float64            `json:"durationSeconds"`
+	Requests        int64              `json:"requests"`
+	Failed          int64              `json:"failed"`
+	AchievedRPS     float64            `json:"achievedRps"`
+	AvgMs           float64            `json:"avgMs"`
+	Percentiles     map[string]float64 `json:"percentiles"`
+}
+
+// TimingSummary is the distribution of one HTTP phase
+type TimingSummary struct {
+	Phase       string             `json:"phase"`
+	Count       int64              `json:"count"`
+	AvgMs       float64            `json:"avgMs"`
+	Percentiles map[string]float64 `json:"percentiles"`
+}
+
+// SampleSummary is one request of the random sample, kept so runs can be
+// compared statistically rather than by their percentiles alone
+type SampleSummary struct {
+	Endpoint   string  `json:"endpoint,omitempty"`
+	DurationMs float64 `json:"durationMs"`
+	Failed     bool    `json:"failed,omitempty"`
+}
+
+// TraceSummary lists the trace IDs of the slowest requests (slowest first)
+// and of the first failed ones, to look them up in a tracing backend
+type TraceSummary struct {
+	Slowest []TracedRequest `json:"slowest,omitempty"`
+	Failed  []TracedRequest `json:"failed,omitempty"`
+}
+
+// TracedRequest is one request with its W3C trace ID
+type TracedRequest struct {
+	TraceID    string    `json:"traceId"`
+	Endpoint   string    `json:"endpoint,omitempty"`
+	Time       time.Time `json:"time"` // Completion time
+	DurationMs float64   `json:"durationMs"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// SearchSummary is the outcome of search mode
+type SearchSummary struct {
+	Strategy string              `json:"strategy"`
+	BestRPS  int                 `json:"bestRps"`
+	Steps    []SearchStepSummary `json:"steps"`
+}
+
+// SearchStepSummary is one rate tried by search mode
+type SearchStepSummary struct {
+	Stage       string  `json:"stage"`
+	TargetRPS   int     `json:"targetRps"`
+	AchievedRPS float64 `json:"achievedRps"`
+	Requests    int64   `json:"requests"`
+	P99Ms       float64 `json:"p99Ms"`
+	ErrorRate   float64 `json:"errorRate"`
+	Passed      bool    `json:"passed"`
+	Reason      string  `json:"reason,omitempty"`
+}
+
+// NewReport assembles the machine-readable report of a run; run may be nil
+func NewReport(cfg *config.Config, results metrics.AggregatedResults, run *runner.BenchmarkResult, info RunInfo) *Report {
+	hostname, _ := os.Hostname()
+	rep := &Report{
+		Metadata: ReportMetadata{
+			FormatVersion:   ReportFormatVersion,
+			Tool:            "benchmarking-tool",
+			ConfigFile:      info.ConfigFile,
+			StartedAt:       info.StartedAt,
+			FinishedAt:      info.FinishedAt,
+			DurationSeconds: info.FinishedAt.Sub(info.StartedAt).Seconds(),
+			Hostname:        hostname,
+			GoVersion:       runtime.Version(),
+		},
+		Config:  summarizeConfig(cfg, results),
+		Results: summarizeResults(results),
+	}
+	if len(results.Endpoints) > 0 {
+		rep.Endpoints = make(map[string]ResultSummary, len(results.Endpoints))
+		for name, res := range results.Endpoints {
+			rep.Endpoints[name] = summarizeResults(res)
+		}
+	}
+	if len(results.BaseURLs) > 0 {
+		rep.BaseURLs = make(map[string]ResultSummary, len(results.BaseURLs))
+		for name, res := range results.BaseURLs {
+			rep.BaseURLs[name] = summarizeResults(res)
+		}
+	}
+	for _, st := range results.Stages {
+		rep.Stages = append(rep.Stages, StageSummary{
+			Name:            st.Name,
+			DurationSeconds: st.Duration.Seconds(),
+			AchievedRPS:     st.AchievedRPS,
+			Results:         summarizeResults(st.Results),
+		})
+	}
+	for _, w := range results.Windows {
+		rep.Windows = append(rep.Windows, WindowSummary{
+			Start:           w.Start,
+			DurationSeconds: w.Duration.Seconds(),
+			Requests:        w.Results.TotalRequests,
+			Failed:          w.Results.FailedRequests,
+			AchievedRPS:     w.AchievedRPS,
+			AvgMs:           millis(w.Results.AvgDuration),
+			Percentiles:     percentileMap(w.Results.Percentiles),
+		})
+	}
+	if hasTimings(results.Timings) {
+		for _, t := range results.Timings {
+			rep.Timings = append(rep.Timings, TimingSummary{
+				Phase:       t.Phase,
+				Count:       t.Count,
+				AvgMs:       millis(t.Avg),
+				Percentiles: percentileMap(t.Percentiles),
+			})
+		}
+	}
+	if run != nil {
+		rep.DroppedRequests = run.DroppedDueToBackpressure
+		if run.Search != nil {
+			rep.Search = &SearchSummary{Strategy: run.Search.Strategy, BestRPS: run.Search.BestRPS}
+			for _, step := range run.Search.Steps {
+				rep.Search.Steps = append(rep.Search.Steps, SearchStepSummary{
+					Stage:       step.Stage,
+					TargetRPS:   step.TargetRPS,
+					AchievedRPS: step.AchievedRPS,
+					Requests:    step.Requests,
+					P99Ms:       millis(step.P99),
+					ErrorRate:   step.ErrorRate,
+					Passed:      step.Passed,
+					Reason:      step.Reason,
+				})
+			}
+		}
+	}
+	for _, d := range results.Samples {
+		rep.Samples = append(rep.Samples, SampleSummary{
+			Endpoint:   d.Endpoint,
+			DurationMs: millis(d.Duration),
+			Failed:     d.IsError || d.StatusCode >= 400,
+		})
+	}
+	if len(results.SlowestTraced) > 0 || len(results.FailedTraced) > 0 {
+		rep.Traces = &TraceSummary{
+			Slowest: tracedRequests(results.SlowestTraced),
+			Failed:  tracedRequests(results.FailedTraced),
+		}
+	}
+	rep.Thresholds = thresholds.Evaluate(cfg.Thresholds, results, rep.DroppedRequests)
+	return rep
+}
+
+// tracedRequests converts details to report units
+func tracedRequests(details []metrics.MetricDetail) []TracedRequest {
+	var out []TracedRequest
+	for _, d := range details {
+		out = append(out, TracedRequest{
+			TraceID:    d.TraceID,
+			Endpoint:   d.Endpoint,
+			Time:       d.Timestamp,
+			DurationMs: millis(d.Duration),
+			StatusCode: d.StatusCode,
+			Error:      d.ErrorMsg,
+		})
+	}
+	return out
+}
+
+// summarizeConfig extracts the settings that shaped the load
+func summarizeConfig(cfg *config.Config, results metrics.AggregatedResults) ConfigSummary {
+	ex := cfg.Execution
+	cs := ConfigSummary{
+		Mode:              ex.Mode,
+		DurationSeconds:   ex.DurationSeconds,
+		RequestTimeoutMs:  ex.RequestTimeoutMs,
+		RequestsPerSecond: ex.RequestsPerSecond,
+		MaxWorkers:        ex.MaxWorkers,
+		Arrival:           ex.Arrival.Type,
+		VirtualUsers:      ex.VirtualUsers,
+		ThinkTimeMs:       ex.ThinkTimeMs,
+		Stages:            ex.Stages,
+		BaseURLs:          cfg.BaseUrls,
+		Endpoints:         make(map[string]string, len(cfg.Endpoints)),
+		Selection:         cfg.EndpointSelection.Strategy,
+		Weights:           cfg.EndpointSelection.Weights,
+	}
+	if ex.Mode == "search" {
+		search := ex.Search
+		cs.Search = &search
+	}
+	for name, ep := range cfg.Endpoints {
+		cs.Endpoints[name] = ep.Method + " " + ep.Path
+	}
+	for _, pv := range results.Percentiles {
+		cs.Percentiles = append(cs.Percentiles, pv.Percentile)
+	}
+	return cs
+}
+
+// summarizeResults converts aggregated results to report units
+func summarizeResults(res metrics.AggregatedResults) ResultSummary {
+	rs := ResultSummary{
+		Requests:          res.TotalRequests,
+		Successful:        res.SuccessfulRequests,
+		Failed:            res.FailedRequests,
+		ElapsedSeconds:    res.Elapsed.Seconds(),
+		AchievedRPS:       res.AchievedRPS,
+		BytesSent:         res.BytesSent,
+		BytesReceived:     res.BytesReceived,
+		SentPerSecond:     res.SentPerSecond,
+		ReceivedPerSecond: res.ReceivedPerSecond,
+		Latency: LatencySummary{
+			MinMs:       millis(res.MinDuration),
+			AvgMs:       millis(res.AvgDuration),
+			MaxMs:       millis(res.MaxDuration),
+			Percentiles: percentileMap(res.Percentiles),
+		},
+		ResponseTime: LatencySummary{
+			MinMs:       millis(minOf(res.ResponseLatency)),
+			AvgMs:       millis(res.AvgResponseTime),
+			MaxMs:       millis(res.MaxResponseTime),
+			Percentiles: percentileMap(res.ResponsePercentiles),
+		},
+		StatusCodes: make(map[string]int64, len(res.StatusCodesCount)),
+		Errors:      res.ErrorDetails,
+	}
+	if res.TotalRequests > 0 {
+		rs.ErrorRate = float64(res.FailedRequests) / float64(res.TotalRequests)
+	}
+	for code, n := range res.StatusCodesCount {
+		rs.StatusCodes[fmt.Sprintf("%d", code)] = n
+	}
+	return rs
+}
+
+// percentileMap keys percentile values in milliseconds by their label
+func percentileMap(values []metrics.PercentileValue) map[string]float64 {
+	out := make(map[string]float64, len(values))
+	for _, pv := range values {
+		out[metrics.PercentileLabel(pv.Percentile)] = millis(pv.Value)
+	}
+	return out
+}
+
+// minOf returns the histogram's minimum, or 0 when there is no histogram
+func minOf(h *metrics.Histogram) time.Duration {
+	if h == nil {
+		return 0
+	}
+	return h.Min()
+}
+
+// millis converts a duration to fractional milliseconds
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// WriteJSON writes the report as indented JSON
+func WriteJSON(w io.Writer, rep *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rep)
+}
+
+// WriteJSONFile writes the report to path
+func WriteJSONFile(path string, rep *Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JSON report: %w", err)
+	}
+	if err := WriteJSON(f, rep); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadJSONFile loads a report previously written by WriteJSONFile
+func ReadJSONFile(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report %s: %w", path, err)
+	}
+	var rep Report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("failed to parse report %s: %w", path, err)
+	}
+	return &rep, nil
+}
@@ -0,0 +1,147 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
+)
+
+func jsonTestResults() metrics.AggregatedResults {
+	collector := metrics.NewCollector()
+	collector.BeginStage("warmup")
+	now := time.Now()
+	for i := 1; i <= 10; i++ {
+		var traceID string
+		if i == 10 {
+			traceID = "0af7651916cd43dd8448eb211c80319c"
+		}
+		collector.AppendDetail(metrics.MetricDetail{
+			Endpoint: "get_user", BaseURL: "http://api", Stage: "warmup", StatusCode: 200,
+			Duration: time.Duration(i) * time.Millisecond, Timestamp: now, BytesReceived: 100, TraceID: traceID,
+		})
+	}
+	collector.AppendDetail(metrics.MetricDetail{
+		Endpoint: "create_user", BaseURL: "http://api", Stage: "warmup", StatusCode: 0,
+		Duration: 5 * time.Millisecond, Timestamp: now, IsError: true, ErrorMsg: "connection refused",
+		TraceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+	})
+	collector.EndStage("warmup")
+	return collector.GetResults()
+}
+
+func TestNewReport(t *testing.T) {
+	cfg := &config.Config{
+		BaseUrls: []string{"http://api"},
+		Execution: config.ExecutionConfig{
+			Mode: "ramp", DurationSeconds: 10, RequestTimeoutMs: 1000, MaxWorkers: 8,
+			Arrival: config.ArrivalConfig{Type: "uniform"},
+			Stages:  []config.StageConfig{{Name: "warmup", StartRPS: 1, TargetRPS: 10, DurationSeconds: 10, Curve: "linear"}},
+		},
+		Endpoints: map[string]config.EndpointConfig{
+			"get_user":    {Path: "/users/{id}", Method: "GET"},
+			"create_user": {Path: "/users", Method: "POST"},
+		},
+		EndpointSelection: config.EndpointSelectionConfig{Strategy: "roundRobin"},
+	}
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	info := RunInfo{ConfigFile: "bench.yml", StartedAt: started, FinishedAt: started.Add(12 * time.Second)}
+	run := &runner.BenchmarkResult{DroppedDueToBackpressure: 3}
+
+	rep := NewReport(cfg, jsonTestResults(), run, info)
+
+	if rep.Metadata.FormatVersion != ReportFormatVersion || rep.Metadata.DurationSeconds != 12 || rep.Metadata.GoVersion == "" {
+		t.Errorf("Unexpected metadata: %+v", rep.Metadata)
+	}
+	if rep.Config.Mode != "ramp" || rep.Config.Endpoints["create_user"] != "POST /users" || len(rep.Config.Stages) != 1 {
+		t.Errorf("Unexpected config summary: %+v", rep.Config)
+	}
+	if len(rep.Config.Percentiles) != len(metrics.DefaultPercentiles) {
+		t.Errorf("Expected the reported percentiles in the config summary, got %v", rep.Config.Percentiles)
+	}
+	res := rep.Results
+	if res.Requests != 11 || res.Failed != 1 || res.ErrorRate != 1.0/11 {
+		t.Errorf("Unexpected totals: %d requests, %d failed, error rate %v", res.Requests, res.Failed, res.ErrorRate)
+	}
+	if res.Latency.MaxMs != 10 || res.Latency.Percentiles["p50"] == 0 {
+		t.Errorf("Unexpected latency summary: %+v", res.Latency)
+	}
+	if res.StatusCodes["200"] != 10 || res.StatusCodes["0"] != 1 || res.Errors["connection refused"] != 1 {
+		t.Errorf("Unexpected status/error breakdown: %v %v", res.StatusCodes, res.Errors)
+	}
+	if rep.Endpoints["get_user"].Requests != 10 || rep.Endpoints["get_user"].BytesReceived != 1000 {
+		t.Errorf("Unexpected get_user breakdown: %+v", rep.Endpoints["get_user"])
+	}
+	if rep.BaseURLs["http://api"].Requests != 11 {
+		t.Errorf("Unexpected base URL breakdown: %+v", rep.BaseURLs)
+	}
+	if len(rep.Stages) != 1 || rep.Stages[0].Results.Requests != 11 {
+		t.Errorf("Unexpected stages: %+v", rep.Stages)
+	}
+	if len(rep.Windows) == 0 || rep.DroppedRequests != 3 {
+		t.Errorf("Expected windows and dropped count, got %d windows, %d dropped", len(rep.Windows), rep.DroppedRequests)
+	}
+	if rep.Search != nil || rep.Timings != nil {
+		t.Error("Search and timings should be omitted when absent")
+	}
+	if tr := rep.Traces; tr == nil || len(tr.Slowest) != 2 || tr.Slowest[0].TraceID != "0af7651916cd43dd8448eb211c80319c" ||
+		tr.Slowest[0].DurationMs != 10 || len(tr.Failed) != 1 || tr.Failed[0].Error != "connection refused" {
+		t.Errorf("Unexpected traced requests: %+v", rep.Traces)
+	}
+}
+
+func TestWriteJSON_RoundTrip(t *testing.T) {
+	cfg := &config.Config{Execution: config.ExecutionConfig{Mode: "search"}}
+	run := &runner.BenchmarkResult{Search: &runner.SearchResult{
+		Strategy: "step",
+		BestRPS:  200,
+		Steps: []runner.SearchStep{
+			{Stage: "search-1", TargetRPS: 200, AchievedRPS: 199, Requests: 995, P99: 80 * time.Millisecond, Passed: true},
+			{Stage: "search-2", TargetRPS: 300, Requests: 1200, P99: 900 * time.Millisecond, Reason: "p99 900ms > 500ms"},
+		},
+	}}
+	rep := NewReport(cfg, jsonTestResults(), run, RunInfo{StartedAt: time.Now(), FinishedAt: time.Now()})
+
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		t.Fatalf("Report is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"metadata", "config", "results", "endpoints", "search"} {
+		if _, ok := raw[key]; !ok {
+			t.Errorf("JSON report should have a %q key", key)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := WriteJSONFile(path, rep); err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadJSONFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back.Search == nil || back.Search.BestRPS != 200 || back.Search.Steps[1].P99Ms != 900 || back.Search.Steps[1].Passed {
+		t.Errorf("Search did not round-trip: %+v", back.Search)
+	}
+	if back.Results.Requests != rep.Results.Requests || back.Endpoints["create_user"].Failed != 1 {
+		t.Errorf("Results did not round-trip: %+v", back.Results)
+	}
+	if back.Config.Search == nil {
+		t.Error("Search mode config should be included")
+	}
+}
+
+func TestReadJSONFile_Errors(t *testing.T) {
+	if _, err := ReadJSONFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing report")
+	}
+}
@@ -0,0 +1,121 @@
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// junitTestSuites is the root element understood by CI test report parsers
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr,omitempty"`
+	Hostname   string          `xml:"hostname,attr,omitempty"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit renders the report's threshold checks as JUnit XML: one test
+// case per check, failing when the threshold was breached, with the measured
+// value in the case output. Checks are grouped by endpoint through classname.
+func WriteJUnit(w io.Writer, rep *Report) error {
+	seconds := strconv.FormatFloat(rep.Metadata.DurationSeconds, 'f', 3, 64)
+	suite := junitTestSuite{
+		Name:     "thresholds",
+		Tests:    len(rep.Thresholds),
+		Time:     seconds,
+		Hostname: rep.Metadata.Hostname,
+		Properties: []junitProperty{
+			{Name: "mode", Value: rep.Config.Mode},
+			{Name: "requests", Value: strconv.FormatInt(rep.Results.Requests, 10)},
+			{Name: "achievedRps", Value: strconv.FormatFloat(rep.Results.AchievedRPS, 'f', 1, 64)},
+		},
+	}
+	if !rep.Metadata.StartedAt.IsZero() {
+		suite.Timestamp = rep.Metadata.StartedAt.UTC().Format("2006-01-02T15:04:05")
+	}
+	if rep.Metadata.ConfigFile != "" {
+		suite.Properties = append(suite.Properties, junitProperty{Name: "configFile", Value: rep.Metadata.ConfigFile})
+	}
+	for _, r := range rep.Thresholds {
+		classname := "benchmark"
+		if r.Scope != "" {
+			classname += "." + r.Scope
+		}
+		tc := junitTestCase{
+			Name:      r.Name(),
+			Classname: classname,
+			Time:      "0",
+			SystemOut: "measured " + r.Measured(),
+		}
+		if !r.Passed {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: r.Reason, Type: "ThresholdExceeded", Text: r.Reason}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	doc := junitTestSuites{
+		Name:     "benchmarking-tool",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     seconds,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteJUnitFile writes the JUnit XML report to path
+func WriteJUnitFile(path string, rep *Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create JUnit report: %w", err)
+	}
+	if err := WriteJUnit(f, rep); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+	return f.Close()
+}
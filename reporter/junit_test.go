@@ -0,0 +1,64 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	rate := 0.01
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed"},
+		Thresholds: config.ThresholdsConfig{
+			ThresholdSet: config.ThresholdSet{MaxErrorRate: &rate},
+			Endpoints: map[string]config.ThresholdSet{
+				"get_user": {Percentiles: map[string]float64{"p95": 200}},
+			},
+		},
+	}
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	info := RunInfo{ConfigFile: "bench.yml", StartedAt: started, FinishedAt: started.Add(12 * time.Second)}
+	rep := NewReport(cfg, jsonTestResults(), nil, info)
+
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "<?xml") {
+		t.Error("JUnit report should start with an XML header")
+	}
+	var doc junitTestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("JUnit report is not valid XML: %v", err)
+	}
+	if doc.Tests != 2 || doc.Failures != 1 || doc.Time != "12.000" || len(doc.Suites) != 1 {
+		t.Fatalf("unexpected totals: %d tests, %d failures, time %s", doc.Tests, doc.Failures, doc.Time)
+	}
+	suite := doc.Suites[0]
+	if suite.Timestamp != "2025-01-02T03:04:05" {
+		t.Errorf("unexpected timestamp %q", suite.Timestamp)
+	}
+
+	// 1 of 11 requests failed, breaching the 1% global limit; get_user is fast
+	errorRate, p95 := suite.Cases[0], suite.Cases[1]
+	if errorRate.Name != "error_rate <= 1%" || errorRate.Classname != "benchmark" || errorRate.Failure == nil {
+		t.Errorf("expected a failing global error rate case, got %+v", errorRate)
+	}
+	if !strings.Contains(errorRate.Failure.Message, "exceeds 1%") || errorRate.SystemOut != "measured 9.09%" {
+		t.Errorf("unexpected failure details %+v / %q", errorRate.Failure, errorRate.SystemOut)
+	}
+	if p95.Name != "get_user p95 <= 200ms" || p95.Classname != "benchmark.get_user" || p95.Failure != nil {
+		t.Errorf("expected a passing get_user p95 case, got %+v", p95)
+	}
+
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	if err := WriteJUnitFile(path, rep); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,223 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
+
+	"golang.org/x/term"
+)
+
+// liveRollingWindows is how many one-second buckets the rolling figures cover
+const liveRollingWindows = 5
+
+// liveLogEvery is how many seconds pass between progress log lines when the
+// output is not a terminal
+const liveLogEvery = 10
+
+// liveBucket aggregates the requests completed in one second
+type liveBucket struct {
+	latency          *metrics.Histogram
+	requests, failed int64
+}
+
+func newLiveBucket() liveBucket {
+	return liveBucket{latency: metrics.NewHistogram(metrics.DefaultHistogramPrecision)}
+}
+
+// liveStats are the rolling figures over the recent buckets
+type liveStats struct {
+	seconds          int
+	requests, failed int64
+	p50, p99         time.Duration
+}
+
+// LiveView shows the progress of a running benchmark. On a terminal it
+// redraws a small dashboard in place every second, keeping log output above
+// it; otherwise it logs a progress line every ten seconds. It is a
+// metrics.Sink: completed requests feed its rolling latency and error figures.
+type LiveView struct {
+	out      io.Writer
+	tty      bool
+	total    time.Duration
+	progress func() runner.Progress
+	tick     time.Duration
+	logEvery int
+	logger   *log.Logger // Progress lines when not a terminal
+
+	mu      sync.Mutex
+	current liveBucket
+	recent  []liveBucket // Completed buckets, oldest first
+	lines   []string     // Dashboard last rendered
+	drawn   int          // Dashboard lines currently on screen
+	logOut  io.Writer    // Log destination before Start, restored by Stop
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewLiveView creates a view writing to out; tty selects the in-place
+// dashboard over log lines. total is the planned run length and progress
+// is usually Runner.Progress.
+func NewLiveView(out io.Writer, tty bool, total time.Duration, progress func() runner.Progress) *LiveView {
+	return &LiveView{
+		out:      out,
+		tty:      tty,
+		total:    total,
+		progress: progress,
+		tick:     time.Second,
+		logEvery: liveLogEvery,
+		logger:   log.New(out, "", log.LstdFlags),
+		current:  newLiveBucket(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// IsTerminal reports whether f is a terminal. Other character devices such
+// as /dev/null are not.
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// Record adds a completed request to the current second
+func (v *LiveView) Record(d metrics.MetricDetail) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.current.latency.Record(d.Duration)
+	v.current.requests++
+	if d.IsError || d.StatusCode >= 400 {
+		v.current.failed++
+	}
+}
+
+// Start begins refreshing. On a terminal the standard logger is routed
+// through the view so log lines do not tear the dashboard.
+func (v *LiveView) Start() {
+	if v.tty {
+		v.logOut = log.Writer()
+		log.SetOutput(v)
+	}
+	go v.loop()
+}
+
+// Stop ends refreshing, leaving the last dashboard on screen
+func (v *LiveView) Stop() {
+	close(v.stop)
+	<-v.done
+	if v.tty {
+		log.SetOutput(v.logOut)
+	}
+}
+
+// Write prints log output above the dashboard (terminal mode)
+func (v *LiveView) Write(p []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.clearLocked()
+	n, err := v.logOut.Write(p)
+	v.drawLocked(v.lines)
+	return n, err
+}
+
+func (v *LiveView) loop() {
+	defer close(v.done)
+	ticker := time.NewTicker(v.tick)
+	defer ticker.Stop()
+	for ticks := 1; ; ticks++ {
+		select {
+		case <-v.stop:
+			return
+		case <-ticker.C:
+		}
+		v.mu.Lock()
+		v.rotateLocked()
+		lines := renderLive(v.progress(), v.statsLocked(), v.total)
+		if v.tty {
+			v.drawLocked(lines)
+		} else if ticks%v.logEvery == 0 {
+			v.logger.Printf("Progress: %s", strings.Join(lines, " | "))
+		}
+		v.mu.Unlock()
+	}
+}
+
+// rotateLocked closes the current second and drops buckets past the rolling window
+func (v *LiveView) rotateLocked() {
+	v.recent = append(v.recent, v.current)
+	if len(v.recent) > liveRollingWindows {
+		v.recent = v.recent[1:]
+	}
+	v.current = newLiveBucket()
+}
+
+// statsLocked merges the recent buckets
+func (v *LiveView) statsLocked() liveStats {
+	s := liveStats{seconds: len(v.recent)}
+	merged := metrics.NewHistogram(metrics.DefaultHistogramPrecision)
+	for _, b := range v.recent {
+		s.requests += b.requests
+		s.failed += b.failed
+		merged.Merge(b.latency)
+	}
+	s.p50 = merged.ValueAtPercentile(50)
+	s.p99 = merged.ValueAtPercentile(99)
+	return s
+}
+
+// clearLocked erases the dashboard from the terminal
+func (v *LiveView) clearLocked() {
+	fmt.Fprint(v.out, strings.Repeat("\x1b[1A\x1b[2K", v.drawn))
+	v.drawn = 0
+}
+
+// drawLocked replaces the dashboard on screen with lines
+func (v *LiveView) drawLocked(lines []string) {
+	v.clearLocked()
+	for _, line := range lines {
+		fmt.Fprintln(v.out, line)
+	}
+	v.lines, v.drawn = lines, len(lines)
+}
+
+// renderLive formats the dashboard lines
+func renderLive(p runner.Progress, s liveStats, total time.Duration) []string {
+	timing := fmt.Sprintf("Elapsed %s / %s (%s left)", clock(p.Elapsed), clock(total), clock(max(total-p.Elapsed, 0)))
+	if p.Stage != "" {
+		timing += ", stage " + p.Stage
+	}
+
+	achieved := 0.0
+	if s.seconds > 0 {
+		achieved = float64(s.requests) / float64(s.seconds)
+	}
+	load := fmt.Sprintf("Users %d, achieved %.1f req/s", p.Users, achieved)
+	if p.TargetRPS > 0 {
+		load = fmt.Sprintf("Target %.1f req/s, achieved %.1f req/s", p.TargetRPS, achieved)
+	}
+
+	workers := fmt.Sprintf("In-flight %d", p.InFlight)
+	if p.QueueCap > 0 {
+		workers += fmt.Sprintf(", queue %d/%d, dropped %d", p.QueueDepth, p.QueueCap, p.Dropped)
+	}
+
+	latency := fmt.Sprintf("Last %ds: no requests", s.seconds)
+	if s.requests > 0 {
+		latency = fmt.Sprintf("Last %ds: p50 %s, p99 %s, errors %.2f%% of %d", s.seconds,
+			formatMs(millis(s.p50)), formatMs(millis(s.p99)), float64(s.failed)/float64(s.requests)*100, s.requests)
+	}
+	return []string{timing, load, workers, latency}
+}
+
+// clock renders a duration as mm:ss
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
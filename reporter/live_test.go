@@ -0,0 +1,123 @@
+package reporter
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
+)
+
+// syncBuffer is a bytes.Buffer safe for the view's goroutine and the test
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestRenderLive(t *testing.T) {
+	p := runner.Progress{
+		Elapsed: 12 * time.Second, Stage: "warmup", TargetRPS: 100,
+		InFlight: 3, QueueDepth: 5, QueueCap: 200, Dropped: 7,
+	}
+	s := liveStats{seconds: 5, requests: 490, failed: 49, p50: 12 * time.Millisecond, p99: 45 * time.Millisecond}
+	got := strings.Join(renderLive(p, s, time.Minute), "\n")
+	for _, want := range []string{
+		"Elapsed 00:12 / 01:00 (00:48 left), stage warmup",
+		"Target 100.0 req/s, achieved 98.0 req/s",
+		"In-flight 3, queue 5/200, dropped 7",
+		"Last 5s: p50 12ms, p99 45ms, errors 10.00% of 490",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("dashboard should contain %q:\n%s", want, got)
+		}
+	}
+
+	closed := strings.Join(renderLive(runner.Progress{Elapsed: 2 * time.Minute, Users: 8}, liveStats{}, time.Minute), "\n")
+	for _, want := range []string{"(00:00 left)", "Users 8, achieved 0.0 req/s", "In-flight 0\n", "no requests"} {
+		if !strings.Contains(closed, want) {
+			t.Errorf("closed-loop dashboard should contain %q:\n%s", want, closed)
+		}
+	}
+}
+
+func TestLiveView_Terminal(t *testing.T) {
+	var out syncBuffer
+	v := NewLiveView(&out, true, time.Minute, func() runner.Progress { return runner.Progress{TargetRPS: 10} })
+	v.tick = 10 * time.Millisecond
+	for i := range 20 {
+		v.Record(metrics.MetricDetail{StatusCode: 200, Duration: time.Duration(i+1) * time.Millisecond})
+	}
+	var logs syncBuffer
+	prevOut, prevFlags := log.Writer(), log.Flags()
+	log.SetOutput(&logs)
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(prevOut)
+		log.SetFlags(prevFlags)
+	}()
+
+	v.Start()
+	time.Sleep(50 * time.Millisecond)
+	log.Print("stage started")
+	v.Stop()
+
+	got := out.String()
+	if !strings.Contains(got, "Target 10.0 req/s") || !strings.Contains(got, "p99 20ms") {
+		t.Errorf("expected the dashboard with the recorded latencies:\n%q", got)
+	}
+	if !strings.Contains(got, "\x1b[1A\x1b[2K") {
+		t.Error("expected the dashboard to be redrawn in place")
+	}
+	if logs.String() != "stage started\n" {
+		t.Errorf("log output should pass through the view, got %q", logs.String())
+	}
+	if log.Writer() != &logs {
+		t.Error("Stop should restore the log output")
+	}
+}
+
+func TestIsTerminal_DevNull(t *testing.T) {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+	if IsTerminal(devNull) {
+		t.Errorf("%s is a character device but not a terminal", os.DevNull)
+	}
+}
+
+func TestLiveView_LogLines(t *testing.T) {
+	var out syncBuffer
+	v := NewLiveView(&out, false, time.Minute, func() runner.Progress { return runner.Progress{Users: 4} })
+	v.tick = 5 * time.Millisecond
+	v.logEvery = 2
+	v.Start()
+	time.Sleep(40 * time.Millisecond)
+	v.Stop()
+
+	got := out.String()
+	if !strings.Contains(got, "Progress: Elapsed") || !strings.Contains(got, "Users 4") {
+		t.Errorf("expected progress log lines, got:\n%s", got)
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Error("log lines should not contain terminal escapes")
+	}
+}
@@ -0,0 +1,122 @@
+package reporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"benchmarking-tool/metrics"
+	"benchmarking-tool/thresholds"
+)
+
+// WriteMarkdown writes a compact summary of the run as GitHub-flavoured
+// Markdown: the overall results, a per-endpoint breakdown and the threshold
+// checks, sized for a pull request comment or a CI job summary.
+func WriteMarkdown(w io.Writer, rep *Report) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "## Benchmark results")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, describeMarkdownRun(rep))
+	fmt.Fprintln(bw)
+	if n := len(rep.Thresholds); n > 0 {
+		if failed := thresholds.Failed(rep.Thresholds); failed > 0 {
+			fmt.Fprintf(bw, "**Thresholds: ❌ %d of %d failed**\n\n", failed, n)
+		} else {
+			fmt.Fprintf(bw, "**Thresholds: ✅ all %d passed**\n\n", n)
+		}
+	}
+
+	labels := make([]string, 0, len(rep.Config.Percentiles))
+	for _, p := range rep.Config.Percentiles {
+		labels = append(labels, metrics.PercentileLabel(p))
+	}
+	headers := append([]string{"Requests", "Failed", "Error rate", "Throughput"}, labels...)
+	WriteMarkdownTable(bw, append(headers, "Max"), [][]string{
+		append(markdownSummaryRow(rep.Results, labels), formatMs(rep.Results.Latency.MaxMs)),
+	})
+	if rep.DroppedRequests > 0 {
+		fmt.Fprintf(bw, "\nDropped requests (queue full): %d\n", rep.DroppedRequests)
+	}
+
+	if len(rep.Endpoints) > 1 {
+		fmt.Fprintln(bw, "\n### Endpoints")
+		fmt.Fprintln(bw)
+		rows := make([][]string, 0, len(rep.Endpoints))
+		for _, name := range sortedKeys(rep.Endpoints) {
+			rows = append(rows, append([]string{name}, markdownSummaryRow(rep.Endpoints[name], labels)...))
+		}
+		WriteMarkdownTable(bw, append([]string{"Endpoint"}, headers...), rows)
+	}
+
+	if len(rep.Thresholds) > 0 {
+		fmt.Fprintln(bw, "\n### Thresholds")
+		fmt.Fprintln(bw)
+		rows := make([][]string, 0, len(rep.Thresholds))
+		for _, c := range rep.Thresholds {
+			verdict := "✅ pass"
+			if !c.Passed {
+				verdict = "❌ " + c.Reason
+			}
+			rows = append(rows, []string{c.Name(), c.Measured(), verdict})
+		}
+		WriteMarkdownTable(bw, []string{"Check", "Measured", "Result"}, rows)
+	}
+	return bw.Flush()
+}
+
+// WriteMarkdownTable writes a Markdown table, escaping the cells
+func WriteMarkdownTable(w io.Writer, headers []string, rows [][]string) {
+	writeMarkdownRow(w, headers)
+	rule := make([]string, len(headers))
+	for i := range rule {
+		rule[i] = "---"
+	}
+	fmt.Fprintf(w, "|%s|\n", strings.Join(rule, "|"))
+	for _, row := range rows {
+		writeMarkdownRow(w, row)
+	}
+}
+
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\r", "", "\n", " ")
+
+func writeMarkdownRow(w io.Writer, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = markdownCellEscaper.Replace(c)
+	}
+	fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+}
+
+// markdownSummaryRow is the requests, failures, error rate, throughput and
+// service time percentiles of a summary
+func markdownSummaryRow(s ResultSummary, labels []string) []string {
+	row := []string{
+		fmt.Sprintf("%d", s.Requests),
+		fmt.Sprintf("%d", s.Failed),
+		fmt.Sprintf("%.2f%%", s.ErrorRate*100),
+		fmt.Sprintf("%.1f req/s", s.AchievedRPS),
+	}
+	for _, label := range labels {
+		row = append(row, formatMs(s.Latency.Percentiles[label]))
+	}
+	return row
+}
+
+// describeMarkdownRun is the one-line description of the load profile
+func describeMarkdownRun(rep *Report) string {
+	parts := []string{fmt.Sprintf("Mode `%s`", rep.Config.Mode), fmt.Sprintf("%ds", rep.Config.DurationSeconds)}
+	if rep.Config.RequestsPerSecond > 0 && rep.Config.Mode == "fixed" {
+		parts = append(parts, fmt.Sprintf("target %d req/s", rep.Config.RequestsPerSecond))
+	}
+	if rep.Config.VirtualUsers > 0 {
+		parts = append(parts, fmt.Sprintf("%d virtual users", rep.Config.VirtualUsers))
+	}
+	if rep.Metadata.ConfigFile != "" {
+		parts = append(parts, "`"+rep.Metadata.ConfigFile+"`")
+	}
+	if !rep.Metadata.StartedAt.IsZero() {
+		parts = append(parts, "started "+rep.Metadata.StartedAt.Format("2006-01-02 15:04:05 MST"))
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,57 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/runner"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	rate := 0.01
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 50},
+		Endpoints: map[string]config.EndpointConfig{
+			"get_user":    {Path: "/users/{id}", Method: "GET"},
+			"create_user": {Path: "/users", Method: "POST"},
+		},
+		Thresholds: config.ThresholdsConfig{ThresholdSet: config.ThresholdSet{MaxErrorRate: &rate}},
+	}
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	info := RunInfo{ConfigFile: "bench.yml", StartedAt: started, FinishedAt: started.Add(12 * time.Second)}
+	rep := NewReport(cfg, jsonTestResults(), &runner.BenchmarkResult{DroppedDueToBackpressure: 2}, info)
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, rep); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, s := range []string{
+		"## Benchmark results",
+		"Mode `fixed` · 10s · target 50 req/s · `bench.yml` · started 2025-01-02 03:04:05 UTC",
+		"**Thresholds: ❌ 1 of 1 failed**",
+		"| Requests | Failed | Error rate | Throughput | p50 | p90 | p95 | p99 | Max |\n|---|---|---|---|---|---|---|---|---|\n| 11 | 1 | 9.09% |",
+		"Dropped requests (queue full): 2",
+		"### Endpoints",
+		"| create_user | 1 | 1 | 100.00% |",
+		"| get_user | 10 | 0 | 0.00% |",
+		"### Thresholds",
+		"| error_rate <= 1% | 9.09% | ❌ measured 9.09% exceeds 1% |",
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Markdown should contain %q:\n%s", s, out)
+		}
+	}
+}
+
+func TestWriteMarkdownTable_Escapes(t *testing.T) {
+	var buf bytes.Buffer
+	WriteMarkdownTable(&buf, []string{"Name", "Error"}, [][]string{{"a|b", "line 1\nline 2"}})
+	want := "| Name | Error |\n|---|---|\n| a\\|b | line 1 line 2 |\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
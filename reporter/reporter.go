@@ -3,10 +3,14 @@ package reporter
 import (
 	"benchmarking-tool/config"
 	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
+	"benchmarking-tool/thresholds"
 	"fmt"
 	"io"
 	"os"
 	"sort"
+	"strings"
+	"text/tabwriter"
 )
 
 // Reporter generates and displays benchmark reports
@@ -23,8 +27,28 @@ func writeMetricRow(w io.Writer, label, value string) {
 	fmt.Fprintf(w, "%-*s  %s\n", metricLabelWidth, label, value)
 }
 
+// writeTable prints an aligned table with a dashed rule under the header
+func writeTable(w io.Writer, headers []string, rows [][]string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	rule := make([]string, len(headers))
+	for i, h := range headers {
+		rule[i] = strings.Repeat("-", len(h))
+	}
+	fmt.Fprintln(tw, strings.Join(rule, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+}
+
 // Generate creates a report from benchmark results and metrics data
 func (r *Reporter) Generate(cfg *config.Config, results metrics.AggregatedResults) {
+	r.GenerateRun(cfg, results, nil)
+}
+
+// GenerateRun is Generate plus the runner's own outcome (search verdicts); run may be nil
+func (r *Reporter) GenerateRun(cfg *config.Config, results metrics.AggregatedResults, run *runner.BenchmarkResult) {
 	out := os.Stdout
 
 	fmt.Fprintln(out, "\n--- Benchmark Report ---")
@@ -37,6 +61,26 @@ func (r *Reporter) Generate(cfg *config.Config, results metrics.AggregatedResult
 	if cfg.Execution.Mode == "fixed" {
 		writeMetricRow(out, "Configured RPS", fmt.Sprintf("%d", cfg.Execution.RequestsPerSecond))
 	}
+	if arrival := describeArrival(cfg.Execution.Arrival); arrival != "" {
+		writeMetricRow(out, "Arrival Process", arrival)
+	}
+	if cfg.Execution.Mode == "concurrency" && len(cfg.Execution.Stages) == 0 {
+		writeMetricRow(out, "Virtual Users", fmt.Sprintf("%d", cfg.Execution.VirtualUsers))
+	}
+	if cfg.Execution.Mode == "concurrency" {
+		writeMetricRow(out, "Think Time", fmt.Sprintf("%dms", cfg.Execution.ThinkTimeMs))
+	}
+	if cfg.Execution.Mode == "max" {
+		writeMetricRow(out, "Configured Workers", fmt.Sprintf("%d", cfg.Execution.MaxWorkers))
+	}
+	if cfg.Execution.Mode == "search" {
+		sc := cfg.Execution.Search
+		writeMetricRow(out, "Search Range", fmt.Sprintf("%d-%d RPS (%s)", sc.StartRPS, sc.MaxRPS, sc.Strategy))
+		writeMetricRow(out, "Step Duration", fmt.Sprintf("%ds", sc.StepDurationSeconds))
+	}
+	if len(cfg.Execution.Stages) > 0 {
+		writeMetricRow(out, "Configured Stages", fmt.Sprintf("%d", len(cfg.Execution.Stages)))
+	}
 
 	writeMetricRow(out, "Total Requests", fmt.Sprintf("%d", results.TotalRequests))
 	writeMetricRow(out, "Successful Requests", fmt.Sprintf("%d", results.SuccessfulRequests))
@@ -46,10 +90,49 @@ func (r *Reporter) Generate(cfg *config.Config, results metrics.AggregatedResult
 		errorRate := float64(results.FailedRequests) / float64(results.TotalRequests) * 100
 		writeMetricRow(out, "Error Rate", fmt.Sprintf("%.2f%%", errorRate))
 	}
+	if results.AchievedRPS > 0 {
+		label := "Achieved RPS"
+		if cfg.Execution.Mode == "max" {
+			label = "Throughput Ceiling"
+		}
+		writeMetricRow(out, label, fmt.Sprintf("%.1f req/s", results.AchievedRPS))
+	}
+
+	if results.BytesSent > 0 || results.BytesReceived > 0 {
+		writeMetricRow(out, "Data Sent", fmt.Sprintf("%s (avg %s/req)", formatBytes(results.BytesSent), formatBytes(results.AvgBytesSent)))
+		writeMetricRow(out, "Data Received", fmt.Sprintf("%s (avg %s/req)", formatBytes(results.BytesReceived), formatBytes(results.AvgBytesReceived)))
+		writeMetricRow(out, "Network Throughput", fmt.Sprintf("%s/s sent, %s/s received",
+			formatBytes(int64(results.SentPerSecond)), formatBytes(int64(results.ReceivedPerSecond))))
+	}
 
 	writeMetricRow(out, "Min Request Time", results.MinDuration.String())
 	writeMetricRow(out, "Max Request Time", results.MaxDuration.String())
 	writeMetricRow(out, "Avg Request Time", results.AvgDuration.String())
+	writeMetricRow(out, "Avg Response Time", results.AvgResponseTime.String())
+	writeMetricRow(out, "Max Response Time", results.MaxResponseTime.String())
+	if run != nil && run.DroppedDueToBackpressure > 0 {
+		writeMetricRow(out, "Dropped Requests", fmt.Sprintf("%d", run.DroppedDueToBackpressure))
+	}
+
+	if len(results.Percentiles) > 0 && results.TotalRequests > 0 {
+		fmt.Fprintln(out, "\nLatency Percentiles:")
+		writePercentileTable(out, results)
+	}
+
+	if hasTimings(results.Timings) {
+		fmt.Fprintln(out, "\nRequest Phases:")
+		writeTimingTable(out, results)
+		writeMetricRow(out, "Reused Connections", fmt.Sprintf("%d of %d", results.ReusedConnections, results.TotalRequests))
+	}
+
+	if len(results.Endpoints) > 1 {
+		fmt.Fprintln(out, "\nEndpoint Breakdown:")
+		writeGroupTable(out, "Endpoint", results.Endpoints)
+	}
+	if len(results.BaseURLs) > 1 {
+		fmt.Fprintln(out, "\nBase URL Breakdown:")
+		writeGroupTable(out, "Base URL", results.BaseURLs)
+	}
 
 	fmt.Fprintln(out, "\nStatus Code Distribution:")
 
@@ -78,5 +161,233 @@ func (r *Reporter) Generate(cfg *config.Config, results metrics.AggregatedResult
 		}
 	}
 
+	if len(results.SlowestTraced) > 0 {
+		fmt.Fprintln(out, "\nSlowest Requests:")
+		writeTraceTable(out, results.SlowestTraced)
+	}
+	if len(results.FailedTraced) > 0 {
+		fmt.Fprintln(out, "\nFirst Failed Requests:")
+		writeTraceTable(out, results.FailedTraced)
+	}
+
+	if run != nil && run.Search != nil {
+		fmt.Fprintf(out, "\nCapacity Search (%s):\n", run.Search.Strategy)
+		writeSearchTable(out, run.Search)
+		best := "none (no step met the SLOs)"
+		if run.Search.BestRPS > 0 {
+			best = fmt.Sprintf("%d RPS", run.Search.BestRPS)
+		}
+		fmt.Fprintln(out)
+		writeMetricRow(out, "Highest Passing RPS", best)
+	} else if len(results.Stages) > 0 {
+		fmt.Fprintln(out, "\nStage Breakdown:")
+		writeStageTable(out, cfg, results.Stages)
+	}
+
+	if !cfg.Thresholds.IsEmpty() {
+		var dropped int64
+		if run != nil {
+			dropped = run.DroppedDueToBackpressure
+		}
+		checks := thresholds.Evaluate(cfg.Thresholds, results, dropped)
+		fmt.Fprintln(out, "\nThresholds:")
+		writeThresholdTable(out, checks)
+		fmt.Fprintln(out)
+		writeMetricRow(out, "Thresholds Passed", fmt.Sprintf("%d of %d", len(checks)-thresholds.Failed(checks), len(checks)))
+	}
+
 	fmt.Fprintln(out, "\n--- End of Report ---")
 }
+
+// sortedKeys returns the keys of a breakdown in name order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatBytes renders a byte count with a binary unit ("512 B", "1.5 KiB")
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, exp := float64(n)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[exp])
+}
+
+// describeArrival summarises a non-uniform arrival process; uniform returns ""
+func describeArrival(a config.ArrivalConfig) string {
+	switch a.Type {
+	case config.ArrivalPoisson:
+		return "poisson"
+	case config.ArrivalBursty:
+		return fmt.Sprintf("bursty (%dms on / %dms off)", a.OnMs, a.OffMs)
+	case config.ArrivalReplay:
+		return fmt.Sprintf("replay (%s)", a.File)
+	}
+	return ""
+}
+
+// writePercentileTable prints the configured percentiles of service and response time
+func writePercentileTable(w io.Writer, results metrics.AggregatedResults) {
+	rows := make([][]string, 0, len(results.Percentiles))
+	for i, pv := range results.Percentiles {
+		response := "-"
+		if i < len(results.ResponsePercentiles) {
+			response = results.ResponsePercentiles[i].Value.String()
+		}
+		rows = append(rows, []string{metrics.PercentileLabel(pv.Percentile), pv.Value.String(), response})
+	}
+	writeTable(w, []string{"Percentile", "Service Time", "Response Time"}, rows)
+}
+
+// hasTimings reports whether any HTTP phase was recorded
+func hasTimings(timings []metrics.TimingResults) bool {
+	for _, t := range timings {
+		if t.Count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTimingTable prints the distribution of each HTTP phase at the
+// configured percentiles, so connection setup can be told apart from server time
+func writeTimingTable(w io.Writer, results metrics.AggregatedResults) {
+	headers := []string{"Phase", "Count", "Avg"}
+	for _, pv := range results.Percentiles {
+		headers = append(headers, metrics.PercentileLabel(pv.Percentile))
+	}
+	rows := make([][]string, 0, len(results.Timings))
+	for _, t := range results.Timings {
+		row := []string{t.Phase, fmt.Sprintf("%d", t.Count), t.Avg.String()}
+		for _, pv := range t.Percentiles {
+			row = append(row, pv.Value.String())
+		}
+		rows = append(rows, row)
+	}
+	writeTable(w, headers, rows)
+}
+
+// writeGroupTable prints request counts, error rate and latency for each
+// group of a breakdown, sorted by name
+func writeGroupTable(w io.Writer, label string, groups map[string]metrics.AggregatedResults) {
+	rows := make([][]string, 0, len(groups))
+	for _, name := range sortedKeys(groups) {
+		res := groups[name]
+		errorRate := 0.0
+		if res.TotalRequests > 0 {
+			errorRate = float64(res.FailedRequests) / float64(res.TotalRequests) * 100
+		}
+		rows = append(rows, []string{
+			name,
+			fmt.Sprintf("%d", res.TotalRequests),
+			fmt.Sprintf("%.2f%%", errorRate),
+			res.AvgDuration.String(),
+			res.Percentile(50).String(),
+			res.Percentile(99).String(),
+			res.MaxDuration.String(),
+			formatBytes(res.AvgBytesSent),
+			formatBytes(res.AvgBytesReceived),
+			formatBytes(int64(res.ReceivedPerSecond)) + "/s",
+		})
+	}
+	writeTable(w, []string{label, "Requests", "Errors", "Avg", "p50", "p99", "Max", "Avg Sent", "Avg Received", "Received/s"}, rows)
+}
+
+// writeStageTable prints achieved throughput and latency per load stage
+func writeStageTable(w io.Writer, cfg *config.Config, stages []metrics.StageResults) {
+	unit := "RPS"
+	if cfg.Execution.Mode == "concurrency" {
+		unit = "VUs"
+	}
+	targets := make(map[string]string, len(cfg.Execution.Stages))
+	for _, st := range cfg.Execution.Stages {
+		start, target := st.Bounds(cfg.Execution.Mode)
+		targets[st.Name] = fmt.Sprintf("%d->%d %s %s", start, target, unit, st.Curve)
+	}
+
+	rows := make([][]string, 0, len(stages))
+	for _, st := range stages {
+		res := st.Results
+		errorRate := 0.0
+		if res.TotalRequests > 0 {
+			errorRate = float64(res.FailedRequests) / float64(res.TotalRequests) * 100
+		}
+		target := targets[st.Name]
+		if target == "" {
+			target = "-"
+		}
+		rows = append(rows, []string{
+			st.Name,
+			target,
+			fmt.Sprintf("%.1f", st.AchievedRPS),
+			fmt.Sprintf("%d", res.TotalRequests),
+			fmt.Sprintf("%.2f%%", errorRate),
+			res.AvgDuration.String(),
+			res.Percentile(99).String(),
+			res.MaxDuration.String(),
+		})
+	}
+	writeTable(w, []string{"Stage", "Target", "Achieved RPS", "Requests", "Errors", "Avg", "p99", "Max"}, rows)
+}
+
+// writeSearchTable prints each rate tried during a search with its SLO verdict
+func writeSearchTable(w io.Writer, search *runner.SearchResult) {
+	rows := make([][]string, 0, len(search.Steps))
+	for _, step := range search.Steps {
+		verdict := "PASS"
+		if !step.Passed {
+			verdict = "FAIL: " + step.Reason
+		}
+		rows = append(rows, []string{
+			step.Stage,
+			fmt.Sprintf("%d", step.TargetRPS),
+			fmt.Sprintf("%.1f", step.AchievedRPS),
+			fmt.Sprintf("%d", step.Requests),
+			fmt.Sprintf("%.2f%%", step.ErrorRate*100),
+			step.P99.String(),
+			verdict,
+		})
+	}
+	writeTable(w, []string{"Step", "Target RPS", "Achieved RPS", "Requests", "Errors", "p99", "Verdict"}, rows)
+}
+
+// writeThresholdTable prints each threshold check with its measured value and verdict
+func writeThresholdTable(w io.Writer, checks []thresholds.Result) {
+	rows := make([][]string, 0, len(checks))
+	for _, c := range checks {
+		verdict := "PASS"
+		if !c.Passed {
+			verdict = "FAIL: " + c.Reason
+		}
+		rows = append(rows, []string{c.Name(), c.Measured(), verdict})
+	}
+	writeTable(w, []string{"Check", "Measured", "Verdict"}, rows)
+}
+
+// consoleTraces is how many traced requests each console table lists; the
+// JSON report has all of them
+const consoleTraces = 5
+
+// writeTraceTable prints traced requests with their trace IDs
+func writeTraceTable(w io.Writer, details []metrics.MetricDetail) {
+	details = details[:min(len(details), consoleTraces)]
+	rows := make([][]string, 0, len(details))
+	for _, d := range details {
+		status := fmt.Sprintf("%d", d.StatusCode)
+		if d.IsError {
+			status = "error"
+		}
+		rows = append(rows, []string{d.TraceID, d.Endpoint, formatMs(millis(d.Duration)), status})
+	}
+	writeTable(w, []string{"Trace ID", "Endpoint", "Time", "Status"}, rows)
+}
@@ -2,6 +2,7 @@ package reporter
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"benchmarking-tool/config"
 	"benchmarking-tool/metrics"
+	"benchmarking-tool/runner"
 )
 
 func TestNewReporter(t *testing.T) {
@@ -142,6 +144,22 @@ func TestReporter_Generate(t *testing.T) {
 			},
 			expectedStrings: []string{"Min Request Time", "Max Request Time"},
 		},
+		{
+			name: "Arrival process",
+			cfg: &config.Config{
+				Execution: config.ExecutionConfig{
+					Mode:              "fixed",
+					DurationSeconds:   60,
+					RequestsPerSecond: 100,
+					Arrival:           config.ArrivalConfig{Type: config.ArrivalBursty, OnMs: 200, OffMs: 800},
+				},
+			},
+			results: metrics.AggregatedResults{
+				StatusCodesCount: make(map[int]int64),
+				ErrorDetails:     make(map[string]int),
+			},
+			expectedStrings: []string{"Arrival Process", "bursty (200ms on / 800ms off)"},
+		},
 		{
 			name: "Configuration info",
 			cfg: &config.Config{
@@ -245,3 +263,423 @@ func TestReporter_ReportStructure(t *testing.T) {
 		t.Error("Report should contain benchmark report header")
 	}
 }
+
+func TestReporter_StageBreakdown(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{
+			Mode:            "ramp",
+			DurationSeconds: 20,
+			Stages: []config.StageConfig{
+				{Name: "warmup", StartRPS: 10, TargetRPS: 100, DurationSeconds: 10, Curve: config.CurveLinear},
+				{Name: "peak", StartRPS: 100, TargetRPS: 100, DurationSeconds: 10, Curve: config.CurveStep},
+			},
+		},
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:      1500,
+		SuccessfulRequests: 1500,
+		StatusCodesCount:   map[int]int64{200: 1500},
+		ErrorDetails:       make(map[string]int),
+		Stages: []metrics.StageResults{
+			{Name: "warmup", Duration: 10 * time.Second, AchievedRPS: 54.8, Results: metrics.AggregatedResults{TotalRequests: 548}},
+			{Name: "peak", Duration: 10 * time.Second, AchievedRPS: 95.2, Results: metrics.AggregatedResults{TotalRequests: 952}},
+		},
+	}
+
+	NewReporter().Generate(cfg, results)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Stage Breakdown", "warmup", "10->100 RPS linear", "54.8", "peak", "95.2", "Configured Stages"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+}
+
+func TestReporter_ConcurrencyMode(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{
+			Mode:            "concurrency",
+			DurationSeconds: 30,
+			Stages: []config.StageConfig{
+				{Name: "rampup", StartUsers: 1, TargetUsers: 25, DurationSeconds: 30, Curve: config.CurveLinear},
+			},
+			ThinkTimeMs: 250,
+		},
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:    300,
+		StatusCodesCount: map[int]int64{200: 300},
+		ErrorDetails:     make(map[string]int),
+		Stages: []metrics.StageResults{
+			{Name: "rampup", Duration: 30 * time.Second, AchievedRPS: 10, Results: metrics.AggregatedResults{TotalRequests: 300}},
+		},
+	}
+
+	NewReporter().Generate(cfg, results)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Think Time", "250ms", "1->25 VUs linear", "rampup"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+}
+
+func TestReporter_MaxModeThroughputCeiling(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "max", DurationSeconds: 10, MaxWorkers: 64},
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:      48213,
+		SuccessfulRequests: 48213,
+		Elapsed:            10 * time.Second,
+		AchievedRPS:        4821.3,
+		StatusCodesCount:   map[int]int64{200: 48213},
+		ErrorDetails:       make(map[string]int),
+	}
+
+	NewReporter().Generate(cfg, results)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Configured Workers", "64", "Throughput Ceiling", "4821.3 req/s"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+}
+
+func TestReporter_SearchResults(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{
+			Mode:            "search",
+			DurationSeconds: 30,
+			Search:          config.SearchConfig{Strategy: "step", StartRPS: 100, MaxRPS: 300, StepRPS: 100, StepDurationSeconds: 10},
+		},
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:    5800,
+		StatusCodesCount: map[int]int64{200: 5800},
+		ErrorDetails:     make(map[string]int),
+	}
+	run := &runner.BenchmarkResult{
+		Search: &runner.SearchResult{
+			Strategy: "step",
+			BestRPS:  200,
+			Steps: []runner.SearchStep{
+				{Stage: "step-1", TargetRPS: 100, AchievedRPS: 99.8, Requests: 998, P99: 40 * time.Millisecond, Passed: true},
+				{Stage: "step-2", TargetRPS: 200, AchievedRPS: 199.1, Requests: 1991, P99: 90 * time.Millisecond, Passed: true},
+				{Stage: "step-3", TargetRPS: 300, AchievedRPS: 281.0, Requests: 2810, P99: 900 * time.Millisecond, Reason: "p99 900ms > 250ms"},
+			},
+		},
+	}
+
+	NewReporter().GenerateRun(cfg, results, run)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Search Range", "100-300 RPS (step)", "Capacity Search", "step-2", "PASS", "FAIL: p99 900ms > 250ms", "Highest Passing RPS", "200 RPS"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+	if strings.Contains(output, "Stage Breakdown") {
+		t.Error("Search report should replace the stage breakdown")
+	}
+}
+
+func TestReporter_CorrectedResponseTime(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 100},
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:    1000,
+		AvgDuration:      20 * time.Millisecond,
+		MaxDuration:      90 * time.Millisecond,
+		AvgResponseTime:  45 * time.Millisecond,
+		MaxResponseTime:  1200 * time.Millisecond,
+		StatusCodesCount: map[int]int64{200: 1000},
+		ErrorDetails:     make(map[string]int),
+	}
+	run := &runner.BenchmarkResult{TotalRequestsMade: 1000, DroppedDueToBackpressure: 17}
+
+	NewReporter().GenerateRun(cfg, results, run)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Avg Response Time", "45ms", "Max Response Time", "1.2s", "Dropped Requests", "17"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+}
+
+func TestReporter_LatencyPercentiles(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	collector := metrics.NewCollectorWithOptions(metrics.Options{Percentiles: []float64{50, 99.9}})
+	for i := 1; i <= 1000; i++ {
+		d := time.Duration(i) * time.Millisecond
+		collector.AppendDetail(metrics.MetricDetail{StatusCode: 200, Duration: d, ResponseTime: 2 * d, Timestamp: time.Now()})
+	}
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 100},
+	}
+
+	NewReporter().Generate(cfg, collector.GetResults())
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Latency Percentiles:", "Percentile", "Service Time", "Response Time", "p50", "p99.9"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+	if strings.Contains(output, "p90") {
+		t.Error("Report should only list the configured percentiles")
+	}
+}
+
+func TestReporter_EndpointAndBaseURLBreakdown(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	collector := metrics.NewCollector()
+	collector.AppendDetail(metrics.MetricDetail{Endpoint: "get_user", BaseURL: "http://primary", StatusCode: 200, Duration: 12 * time.Millisecond})
+	collector.AppendDetail(metrics.MetricDetail{Endpoint: "create_user", BaseURL: "http://backup", StatusCode: 500, Duration: 80 * time.Millisecond})
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 100},
+	}
+
+	NewReporter().Generate(cfg, collector.GetResults())
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Endpoint Breakdown:", "get_user", "create_user", "100.00%", "Base URL Breakdown:", "http://primary", "http://backup"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+	if strings.Index(output, "create_user") > strings.Index(output, "get_user") {
+		t.Error("Endpoints should be listed in name order")
+	}
+}
+
+func TestReporter_RequestPhases(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	collector := metrics.NewCollector()
+	collector.AppendDetail(metrics.MetricDetail{StatusCode: 200, Duration: 50 * time.Millisecond, Timings: metrics.RequestTimings{
+		DNSLookup: 2 * time.Millisecond, TCPConnect: 3 * time.Millisecond, TimeToFirstByte: 40 * time.Millisecond, Download: 5 * time.Millisecond,
+	}})
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 100},
+	}
+
+	NewReporter().Generate(cfg, collector.GetResults())
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Request Phases:", "DNS Lookup", "TCP Connect", "TLS Handshake", "Time to First Byte", "Download", "40ms", "Reused Connections", "0 of 1"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB", 3 << 30: "3.0 GiB"} {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestReporter_DataTransferred(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 100},
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:     1000,
+		BytesSent:         256 << 10,
+		BytesReceived:     10 << 20,
+		AvgBytesSent:      262,
+		AvgBytesReceived:  10485,
+		SentPerSecond:     25 << 10,
+		ReceivedPerSecond: 1 << 20,
+		StatusCodesCount:  map[int]int64{200: 1000},
+		ErrorDetails:      make(map[string]int),
+		Endpoints: map[string]metrics.AggregatedResults{
+			"upload":   {TotalRequests: 500, AvgBytesSent: 524, ReceivedPerSecond: 512},
+			"download": {TotalRequests: 500, AvgBytesReceived: 20 << 10, ReceivedPerSecond: 1 << 20},
+		},
+	}
+
+	NewReporter().Generate(cfg, results)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Data Sent", "256.0 KiB (avg 262 B/req)", "Data Received", "10.0 MiB", "Network Throughput", "25.0 KiB/s sent, 1.0 MiB/s received",
+		"Avg Sent", "Avg Received", "524 B", "20.0 KiB", "1.0 MiB/s"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+}
+
+func TestReporter_Thresholds(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	maxErrorRate, maxDropped := 0.01, int64(0)
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 100},
+		Thresholds: config.ThresholdsConfig{
+			ThresholdSet: config.ThresholdSet{MaxErrorRate: &maxErrorRate},
+			MaxDropped:   &maxDropped,
+		},
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:      100,
+		SuccessfulRequests: 98,
+		FailedRequests:     2,
+		StatusCodesCount:   map[int]int64{200: 98, 500: 2},
+		ErrorDetails:       make(map[string]int),
+	}
+
+	NewReporter().GenerateRun(cfg, results, &runner.BenchmarkResult{})
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Thresholds:", "error_rate <= 1%", "FAIL: measured 2.00% exceeds 1%", "dropped_requests <= 0", "PASS", "Thresholds Passed", "1 of 2"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+}
+
+func TestReporter_TracedRequests(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	cfg := &config.Config{
+		Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10, RequestsPerSecond: 100},
+	}
+	slowest := make([]metrics.MetricDetail, 0, 8)
+	for i := range 8 {
+		slowest = append(slowest, metrics.MetricDetail{
+			TraceID: fmt.Sprintf("%032d", i), Endpoint: "get_user", StatusCode: 200, Duration: time.Duration(900-i) * time.Millisecond,
+		})
+	}
+	results := metrics.AggregatedResults{
+		TotalRequests:    100,
+		StatusCodesCount: map[int]int64{200: 99, 0: 1},
+		ErrorDetails:     map[string]int{"timeout": 1},
+		SlowestTraced:    slowest,
+		FailedTraced: []metrics.MetricDetail{
+			{TraceID: "4bf92f3577b34da6a3ce929d0e0e4736", Endpoint: "create_user", IsError: true, Duration: 2 * time.Second},
+		},
+	}
+
+	NewReporter().Generate(cfg, results)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, s := range []string{"Slowest Requests:", "Trace ID", fmt.Sprintf("%032d", 4), "900ms", "First Failed Requests:", "4bf92f3577b34da6a3ce929d0e0e4736", "error"} {
+		if !strings.Contains(output, s) {
+			t.Errorf("Report should contain '%s'", s)
+		}
+	}
+	if strings.Contains(output, fmt.Sprintf("%032d", 5)) {
+		t.Errorf("Console should list at most %d slow requests", consoleTraces)
+	}
+}
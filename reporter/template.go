@@ -0,0 +1,82 @@
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// reportFuncs are available to the built-in HTML report and to user templates
+var reportFuncs = template.FuncMap{
+	"ms":    formatMs,
+	"pct":   func(rate float64) string { return fmt.Sprintf("%.2f%%", rate*100) },
+	"rps":   func(v float64) string { return fmt.Sprintf("%.1f", v) },
+	"bytes": formatBytes,
+	"lookup": func(m map[string]float64, key string) string {
+		if v, ok := m[key]; ok {
+			return formatMs(v)
+		}
+		return "-"
+	},
+	"json": func(v any) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+}
+
+// Template is a user-defined report layout, rendered with the Report model
+type Template struct {
+	tmpl interface {
+		Execute(w io.Writer, data any) error
+	}
+}
+
+// ParseTemplateFile parses a report template. Files ending in .html or .htm
+// use html/template, which escapes values for HTML; others use text/template.
+func ParseTemplateFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report template: %w", err)
+	}
+	name := filepath.Base(path)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".html", ".htm":
+		t, err := htmltemplate.New(name).Funcs(reportFuncs).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse report template: %w", err)
+		}
+		return &Template{tmpl: t}, nil
+	default:
+		t, err := template.New(name).Funcs(reportFuncs).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse report template: %w", err)
+		}
+		return &Template{tmpl: t}, nil
+	}
+}
+
+// Execute renders the template with rep
+func (t *Template) Execute(w io.Writer, rep *Report) error {
+	if err := t.tmpl.Execute(w, rep); err != nil {
+		return fmt.Errorf("failed to render report template: %w", err)
+	}
+	return nil
+}
+
+// ExecuteFile renders the template with rep to path
+func (t *Template) ExecuteFile(path string, rep *Report) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create templated report: %w", err)
+	}
+	if err := t.Execute(f, rep); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
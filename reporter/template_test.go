@@ -0,0 +1,81 @@
+package reporter
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+)
+
+func writeTemplate(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func templateTestReport() *Report {
+	cfg := &config.Config{Execution: config.ExecutionConfig{Mode: "fixed", DurationSeconds: 10}}
+	started := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return NewReport(cfg, jsonTestResults(), nil, RunInfo{ConfigFile: "<bench>.yml", StartedAt: started, FinishedAt: started.Add(time.Second)})
+}
+
+func TestTemplate_Text(t *testing.T) {
+	path := writeTemplate(t, "report.txt", `{{.Config.Mode}} run of {{.Metadata.ConfigFile}}
+requests={{.Results.Requests}} errors={{pct .Results.ErrorRate}} p50={{ms .Results.Latency.Percentiles.p50}} p99.9={{lookup .Results.Latency.Percentiles "p99.9"}}
+{{range $name, $ep := .Endpoints}}{{$name}}: {{$ep.Requests}} in {{bytes $ep.BytesReceived}}
+{{end}}`)
+	tmpl, err := ParseTemplateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateTestReport()); err != nil {
+		t.Fatal(err)
+	}
+	want := "fixed run of <bench>.yml\nrequests=11 errors=9.09% p50=5ms p99.9=-\ncreate_user: 1 in 0 B\nget_user: 10 in 1000 B\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestTemplate_HTMLEscapes(t *testing.T) {
+	path := writeTemplate(t, "report.HTML", `<h1>{{.Metadata.ConfigFile}}</h1><pre>{{json .Config.Percentiles}}</pre>`)
+	tmpl, err := ParseTemplateFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "out.html")
+	if err := tmpl.ExecuteFile(out, templateTestReport()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "<h1>&lt;bench&gt;.yml</h1>") || !strings.Contains(got, "50,\n  90") {
+		t.Errorf("expected escaped HTML with the JSON helper, got %s", got)
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	if _, err := ParseTemplateFile(filepath.Join(t.TempDir(), "missing.tmpl")); err == nil {
+		t.Error("expected an error for a missing template")
+	}
+	if _, err := ParseTemplateFile(writeTemplate(t, "bad.tmpl", "{{.Results")); err == nil || !strings.Contains(err.Error(), "parse") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+	tmpl, err := ParseTemplateFile(writeTemplate(t, "field.tmpl", "{{.NoSuchField}}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpl.Execute(&bytes.Buffer{}, templateTestReport()); err == nil || !strings.Contains(err.Error(), "NoSuchField") {
+		t.Errorf("expected an execution error naming the field, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package runner
+
+import (
+	"benchmarking-tool/config"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// pacer decides when the scheduler releases the next job. Implementations
+// keep a long-run mean of rps arrivals per second; setRate may be called
+// concurrently with wait (ramp and search retune it while scheduling).
+// wait returns the slot's intended send time, which may be slightly earlier
+// than when it actually returns.
+type pacer interface {
+	wait(ctx context.Context) (time.Time, error)
+	setRate(rps float64)
+}
+
+// newPacer builds the pacer for the configured arrival process
+func newPacer(arrival config.ArrivalConfig, rps float64, burst int) pacer {
+	switch arrival.Type {
+	case config.ArrivalPoisson:
+		return &intervalPacer{rps: rps, gap: poissonGap}
+	case config.ArrivalBursty:
+		return newBurstyPacer(rps, time.Duration(arrival.OnMs)*time.Millisecond, time.Duration(arrival.OffMs)*time.Millisecond)
+	case config.ArrivalReplay:
+		return &intervalPacer{rps: rps, gap: replayGap(arrival.ReplayGaps())}
+	default: // uniform
+		return &limiterPacer{limiter: rate.NewLimiter(rate.Limit(rps), burst)}
+	}
+}
+
+// limiterPacer is the token bucket: near-uniform spacing with an optional burst
+type limiterPacer struct {
+	limiter *rate.Limiter
+}
+
+func (p *limiterPacer) wait(ctx context.Context) (time.Time, error) {
+	res := p.limiter.Reserve()
+	if !res.OK() {
+		return time.Time{}, fmt.Errorf("rate limiter cannot grant a token")
+	}
+	at := time.Now().Add(res.Delay())
+	return at, sleepUntil(ctx, at, res.Cancel)
+}
+
+func (p *limiterPacer) setRate(rps float64) {
+	p.limiter.SetLimit(rate.Limit(rps))
+}
+
+// maxPacerLag bounds catch-up: if the scheduler stopped asking for slots
+// (between search steps, say) the timeline restarts from now instead of
+// firing every missed slot at once.
+const maxPacerLag = 250 * time.Millisecond
+
+// intervalPacer schedules arrivals on an absolute timeline, drawing each gap
+// from gap(rps). Slots that are already due fire immediately, so timer
+// overshoot does not lower the mean rate.
+type intervalPacer struct {
+	mu    sync.Mutex
+	rps   float64
+	next  time.Time
+	gap   func(rps float64) time.Duration
+	shift func(t time.Time) time.Time // Optional: moves a slot to the next allowed instant
+}
+
+func (p *intervalPacer) wait(ctx context.Context) (time.Time, error) {
+	p.mu.Lock()
+	if now := time.Now(); p.next.IsZero() || now.Sub(p.next) > maxPacerLag {
+		p.next = now
+	} else {
+		p.next = p.next.Add(p.gap(p.rps))
+	}
+	if p.shift != nil {
+		p.next = p.shift(p.next)
+	}
+	at := p.next
+	p.mu.Unlock()
+
+	return at, sleepUntil(ctx, at, nil)
+}
+
+// sleepUntil waits for at or ctx, calling onCancel (if set) when ctx wins
+func sleepUntil(ctx context.Context, at time.Time, onCancel func()) error {
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		if onCancel != nil {
+			onCancel()
+		}
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (p *intervalPacer) setRate(rps float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rps = rps
+}
+
+// poissonGap draws exponentially distributed gaps, giving a Poisson arrival process
+func poissonGap(rps float64) time.Duration {
+	return time.Duration(rand.ExpFloat64() / rps * float64(time.Second))
+}
+
+// replayGap cycles through recorded gaps, scaled so their mean is 1/rps
+func replayGap(gaps []time.Duration) func(rps float64) time.Duration {
+	var total time.Duration
+	for _, g := range gaps {
+		total += g
+	}
+	mean := float64(total) / float64(len(gaps))
+	i := 0
+	return func(rps float64) time.Duration {
+		g := gaps[i%len(gaps)]
+		i++
+		return time.Duration(float64(g) / mean / rps * float64(time.Second))
+	}
+}
+
+// newBurstyPacer alternates on and off periods: arrivals are evenly spaced
+// during each burst at the rate that keeps rps on average, and none are
+// sent while off.
+func newBurstyPacer(rps float64, on, off time.Duration) *intervalPacer {
+	period := on + off
+	onShare := float64(on) / float64(period)
+	origin := time.Now()
+	return &intervalPacer{
+		rps: rps,
+		gap: func(rps float64) time.Duration {
+			return time.Duration(onShare / rps * float64(time.Second))
+		},
+		shift: func(t time.Time) time.Time {
+			phase := t.Sub(origin) % period
+			if phase < on {
+				return t
+			}
+			return t.Add(period - phase)
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+)
+
+// countArrivals runs the pacer for d and returns the arrival times
+func countArrivals(t *testing.T, pc pacer, d time.Duration) []time.Time {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	var arrivals []time.Time
+	for {
+		if _, err := pc.wait(ctx); err != nil {
+			return arrivals
+		}
+		arrivals = append(arrivals, time.Now())
+	}
+}
+
+func TestPacers_KeepMeanRate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		arrival config.ArrivalConfig
+	}{
+		{"uniform", config.ArrivalConfig{Type: config.ArrivalUniform}},
+		{"poisson", config.ArrivalConfig{Type: config.ArrivalPoisson}},
+		{"bursty", config.ArrivalConfig{Type: config.ArrivalBursty, OnMs: 100, OffMs: 100}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			arrivals := countArrivals(t, newPacer(tc.arrival, 200, 1), time.Second)
+			// 200 expected; Poisson has a standard deviation of ~14 over one second.
+			if n := len(arrivals); n < 150 || n > 250 {
+				t.Fatalf("expected ~200 arrivals in 1s, got %d", n)
+			}
+		})
+	}
+}
+
+func TestBurstyPacer_SilentWhileOff(t *testing.T) {
+	on, off := 100*time.Millisecond, 150*time.Millisecond
+	pc := newBurstyPacer(100, on, off)
+	start := time.Now()
+	arrivals := countArrivals(t, pc, 600*time.Millisecond)
+	if len(arrivals) == 0 {
+		t.Fatal("expected arrivals")
+	}
+	const slack = 15 * time.Millisecond
+	for _, at := range arrivals {
+		phase := at.Sub(start) % (on + off)
+		if phase > on+slack && phase < on+off-slack {
+			t.Fatalf("arrival at %v falls inside an off period", at.Sub(start))
+		}
+	}
+}
+
+func TestReplayGap_ScalesToRate(t *testing.T) {
+	gap := replayGap([]time.Duration{10 * time.Millisecond, 30 * time.Millisecond})
+	// Recorded mean is 20ms; at 100 RPS the mean must become 10ms.
+	want := []time.Duration{5 * time.Millisecond, 15 * time.Millisecond, 5 * time.Millisecond}
+	for i, w := range want {
+		if got := gap(100); got != w {
+			t.Fatalf("gap %d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestIntervalPacer_RestartsAfterIdle(t *testing.T) {
+	pc := &intervalPacer{rps: 10, gap: func(rps float64) time.Duration { return 100 * time.Millisecond }}
+	ctx := context.Background()
+	if _, err := pc.wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(2 * maxPacerLag)
+
+	// The missed slots are not replayed: the second arrival waits a full gap.
+	start := time.Now()
+	if _, err := pc.wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pc.wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Fatalf("expected the pacer to restart its timeline, got two arrivals in %v", elapsed)
+	}
+}
+
+func TestPacers_ReturnIntendedTime(t *testing.T) {
+	for _, arrival := range []config.ArrivalConfig{{Type: config.ArrivalUniform}, {Type: config.ArrivalPoisson}} {
+		t.Run(arrival.Type, func(t *testing.T) {
+			pc := newPacer(arrival, 50, 1)
+			for range 5 {
+				intended, err := pc.wait(context.Background())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if lag := time.Since(intended); lag < 0 || lag > 20*time.Millisecond {
+					t.Fatalf("intended time should be just before wait returns, lag %v", lag)
+				}
+			}
+		})
+	}
+}
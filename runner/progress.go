@@ -0,0 +1,72 @@
+package runner
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Progress is a live snapshot of a running benchmark, for progress displays
+type Progress struct {
+	Elapsed    time.Duration
+	Stage      string  // Current load stage ("" outside staged modes)
+	TargetRPS  float64 // Scheduler rate in open-loop modes (0 in closed-loop modes)
+	Users      int64   // Active virtual users in closed-loop modes
+	InFlight   int64   // Requests being sent right now
+	QueueDepth int     // Jobs waiting for a worker
+	QueueCap   int
+	Dropped    int64 // Schedule slots dropped because the queue was full
+}
+
+// liveState holds what Progress reports; every field is safe for concurrent use
+type liveState struct {
+	started   atomic.Int64 // UnixNano; 0 before Run
+	stage     atomic.Pointer[string]
+	targetRPS atomic.Uint64 // math.Float64bits of the pacer rate
+	users     atomic.Int64
+	inFlight  atomic.Int64
+	pool      atomic.Pointer[pool]
+}
+
+// Progress returns a snapshot of the run; it may be called from any goroutine
+func (r *Runner) Progress() Progress {
+	var p Progress
+	if started := r.live.started.Load(); started != 0 {
+		p.Elapsed = time.Since(time.Unix(0, started))
+	}
+	if stage := r.live.stage.Load(); stage != nil {
+		p.Stage = *stage
+	}
+	p.TargetRPS = math.Float64frombits(r.live.targetRPS.Load())
+	p.Users = r.live.users.Load()
+	p.InFlight = r.live.inFlight.Load()
+	if pl := r.live.pool.Load(); pl != nil {
+		p.QueueDepth = len(pl.jobs)
+		p.QueueCap = cap(pl.jobs)
+		p.Dropped = pl.dropped.Load()
+	}
+	return p
+}
+
+// beginStage starts a collector stage and shows it in Progress
+func (r *Runner) beginStage(name string) {
+	r.live.stage.Store(&name)
+	r.collector.BeginStage(name)
+}
+
+// startPacer builds the configured pacer at rps, reporting its rate in Progress
+func (r *Runner) startPacer(rps float64) pacer {
+	r.live.targetRPS.Store(math.Float64bits(rps))
+	return trackedPacer{pacer: newPacer(r.cfg.Execution.Arrival, rps, r.cfg.Execution.RateBurst), live: &r.live}
+}
+
+// trackedPacer records every rate change for Progress
+type trackedPacer struct {
+	pacer
+	live *liveState
+}
+
+func (p trackedPacer) setRate(rps float64) {
+	p.live.targetRPS.Store(math.Float64bits(rps))
+	p.pacer.setRate(rps)
+}
@@ -10,16 +10,16 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"math/big"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
-
-	"golang.org/x/time/rate"
 )
 
 // Runner executes the benchmark tests with the new configuration format
@@ -31,6 +31,7 @@ type Runner struct {
 	endpointIdx   atomic.Uint64
 	urlIdx        atomic.Uint64
 	resultsCh     chan metrics.MetricDetail
+	live          liveState // Read by Progress
 }
 
 // BenchmarkResult holds the outcome of a benchmark run
@@ -41,6 +42,26 @@ type BenchmarkResult struct {
 	DroppedDueToBackpressure int64
 	RequestTimings           []time.Duration
 	StatusCodes              map[int]int64
+	Search                   *SearchResult // Set in search mode
+}
+
+// SearchResult is the outcome of a search-mode run
+type SearchResult struct {
+	Strategy string
+	Steps    []SearchStep // In the order they ran
+	BestRPS  int          // Highest target RPS that met every SLO (0 if none did)
+}
+
+// SearchStep records one rate tried during a search and its SLO verdict
+type SearchStep struct {
+	Stage       string // Stage name in metrics.AggregatedResults.Stages
+	TargetRPS   int
+	AchievedRPS float64
+	Requests    int64
+	P99         time.Duration
+	ErrorRate   float64 // Failed fraction, 0..1
+	Passed      bool
+	Reason      string // Why the step failed; empty when it passed
 }
 
 // NewRunner creates a new benchmark runner
@@ -53,10 +74,10 @@ func NewRunner(cfg *config.Config, collector *metrics.Collector) *Runner {
 	}
 	sort.Strings(epNames)
 
-	mw := cfg.Execution.MaxWorkers
-	if mw < 1 {
-		mw = 1
-	}
+	// Idle connections are kept for every worker, or every virtual user in
+	// concurrency mode, which has no worker pool and whose user count may
+	// exceed the capped maxWorkers
+	mw := max(cfg.Execution.MaxWorkers, cfg.PeakUsers(), 1)
 	nHosts := len(cfg.BaseUrls)
 	if nHosts < 1 {
 		nHosts = 1
@@ -80,20 +101,44 @@ func NewRunner(cfg *config.Config, collector *metrics.Collector) *Runner {
 	}
 }
 
+// job is a unit of work handed from the scheduler to a worker
+type job struct {
+	stage    string    // Load stage the job was scheduled in ("" outside staged modes)
+	intended time.Time // When the scheduler meant the request to start
+}
+
 // Run starts the benchmark based on the configuration
 func (r *Runner) Run() (*BenchmarkResult, error) {
 	log.Printf("Starting benchmark in '%s' mode.", r.cfg.Execution.Mode)
+	r.live.started.Store(time.Now().UnixNano())
 
 	switch r.cfg.Execution.Mode {
 	case "fixed":
 		return r.runFixedRPSMode()
 	case "ramp":
-		return nil, fmt.Errorf("ramp up mode not yet implemented")
+		return r.runRampMode()
+	case "concurrency":
+		return r.runConcurrencyMode()
+	case "max":
+		return r.runMaxThroughputMode()
+	case "search":
+		return r.runSearchMode()
 	default:
 		return nil, fmt.Errorf("unknown mode: %s", r.cfg.Execution.Mode)
 	}
 }
 
+// checkTargets verifies there is something to send requests to
+func (r *Runner) checkTargets() error {
+	if len(r.cfg.Endpoints) == 0 {
+		return fmt.Errorf("no endpoints configured")
+	}
+	if len(r.cfg.BaseUrls) == 0 {
+		return fmt.Errorf("no base URLs configured")
+	}
+	return nil
+}
+
 // runFixedRPSMode executes the benchmark at a fixed number of requests per second
 func (r *Runner) runFixedRPSMode() (*BenchmarkResult, error) {
 	log.Printf("Running in fixed RPS mode: %d RPS for %d seconds (workers=%d, queue=%d, burst=%d).",
@@ -103,74 +148,537 @@ func (r *Runner) runFixedRPSMode() (*BenchmarkResult, error) {
 	if r.cfg.Execution.RequestsPerSecond <= 0 {
 		return nil, fmt.Errorf("requestsPerSecond must be positive for fixed mode")
 	}
-	if len(r.cfg.Endpoints) == 0 {
-		return nil, fmt.Errorf("no endpoints configured")
+	if err := r.checkTargets(); err != nil {
+		return nil, err
 	}
-	if len(r.cfg.BaseUrls) == 0 {
-		return nil, fmt.Errorf("no base URLs configured")
+
+	totalDuration := time.Duration(r.cfg.Execution.DurationSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), totalDuration)
+	defer cancel()
+
+	p := r.startPool()
+	pc := r.startPacer(float64(r.cfg.Execution.RequestsPerSecond))
+	p.schedule(ctx, pc, job{})
+	droppedN := p.stop()
+
+	log.Println("Benchmark duration reached.")
+	return r.finish("Fixed RPS", droppedN), nil
+}
+
+// runRampMode walks the configured stages, retuning the scheduler's rate as it goes
+func (r *Runner) runRampMode() (*BenchmarkResult, error) {
+	stages := r.cfg.Execution.Stages
+	log.Printf("Running in ramp mode: %d stages over %d seconds (workers=%d, queue=%d, burst=%d).",
+		len(stages), r.cfg.Execution.DurationSeconds,
+		r.cfg.Execution.MaxWorkers, r.cfg.Execution.MaxQueueDepth, r.cfg.Execution.RateBurst)
+
+	if len(stages) == 0 {
+		return nil, fmt.Errorf("ramp mode requires at least one stage")
+	}
+	if err := r.checkTargets(); err != nil {
+		return nil, err
+	}
+
+	p := r.startPool()
+	pc := r.startPacer(stageRate(stages[0], 0))
+
+	for _, st := range stages {
+		stageDuration := time.Duration(st.DurationSeconds) * time.Second
+		log.Printf("Stage '%s': %d -> %d RPS (%s) for %s.", st.Name, st.StartRPS, st.TargetRPS, st.Curve, stageDuration)
+
+		ctx, cancel := context.WithTimeout(context.Background(), stageDuration)
+		r.beginStage(st.Name)
+		waitRetune := startRetune(ctx, pc, st, stageDuration)
+		p.schedule(ctx, pc, job{stage: st.Name})
+		r.collector.EndStage(st.Name)
+		cancel()
+		waitRetune() // A late retune must not overwrite the next stage's rate
+	}
+	droppedN := p.stop()
+
+	log.Println("Ramp profile completed.")
+	return r.finish("Ramp", droppedN), nil
+}
+
+// runConcurrencyMode runs closed-loop virtual users: each sends a request, waits for
+// the response, optionally thinks, and repeats. With stages the user count follows
+// the stage curves; otherwise virtualUsers stay active for the whole duration.
+func (r *Runner) runConcurrencyMode() (*BenchmarkResult, error) {
+	stages := r.cfg.Execution.Stages
+	thinkTime := time.Duration(r.cfg.Execution.ThinkTimeMs) * time.Millisecond
+	if len(stages) > 0 {
+		log.Printf("Running in concurrency mode: %d stages over %d seconds (think time %s).",
+			len(stages), r.cfg.Execution.DurationSeconds, thinkTime)
+	} else {
+		log.Printf("Running in concurrency mode: %d virtual users for %d seconds (think time %s).",
+			r.cfg.Execution.VirtualUsers, r.cfg.Execution.DurationSeconds, thinkTime)
+	}
+
+	if len(stages) == 0 && r.cfg.Execution.VirtualUsers <= 0 {
+		return nil, fmt.Errorf("virtualUsers must be positive for concurrency mode")
+	}
+	if err := r.checkTargets(); err != nil {
+		return nil, err
 	}
 
 	totalDuration := time.Duration(r.cfg.Execution.DurationSeconds) * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), totalDuration)
 	defer cancel()
 
-	maxWorkers := r.cfg.Execution.MaxWorkers
-	queueDepth := r.cfg.Execution.MaxQueueDepth
-	jobs := make(chan struct{}, queueDepth)
-	var dropped atomic.Int64
+	users := r.startUserGroup(ctx, thinkTime)
+	if len(stages) == 0 {
+		users.scaleTo(r.cfg.Execution.VirtualUsers)
+		<-ctx.Done()
+	}
+	for _, st := range stages {
+		stageDuration := time.Duration(st.DurationSeconds) * time.Second
+		log.Printf("Stage '%s': %d -> %d virtual users (%s) for %s.", st.Name, st.StartUsers, st.TargetUsers, st.Curve, stageDuration)
+
+		users.setStage(st.Name)
+		r.beginStage(st.Name)
+		followUserCurve(ctx, users, st, stageDuration)
+		r.collector.EndStage(st.Name)
+	}
+	users.stop()
+
+	log.Println("Benchmark duration reached.")
+	return r.finish("Concurrency", 0), nil
+}
+
+// runMaxThroughputMode removes the rate limiter: MaxWorkers workers send back-to-back
+// requests for the whole duration, so throughput is bounded only by the target
+func (r *Runner) runMaxThroughputMode() (*BenchmarkResult, error) {
+	log.Printf("Running in max throughput mode: %d workers for %d seconds.",
+		r.cfg.Execution.MaxWorkers, r.cfg.Execution.DurationSeconds)
+
+	if err := r.checkTargets(); err != nil {
+		return nil, err
+	}
+
+	totalDuration := time.Duration(r.cfg.Execution.DurationSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), totalDuration)
+	defer cancel()
+
+	users := r.startUserGroup(ctx, 0)
+	users.scaleTo(r.cfg.Execution.MaxWorkers)
+	<-ctx.Done()
+	users.stop()
+
+	log.Println("Benchmark duration reached.")
+	return r.finish("Max throughput", 0), nil
+}
+
+// followUserCurve scales the user group along the stage curve until the stage
+// duration elapses or ctx is done
+func followUserCurve(ctx context.Context, users *userGroup, st config.StageConfig, stageDuration time.Duration) {
+	start := time.Now()
+	from, to := float64(st.StartUsers), float64(st.TargetUsers)
+	users.scaleTo(int(math.Round(curveValue(st.Curve, from, to, 0))))
+
+	ticker := time.NewTicker(rampRetuneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			elapsed := time.Since(start)
+			if elapsed >= stageDuration {
+				return
+			}
+			progress := float64(elapsed) / float64(stageDuration)
+			users.scaleTo(int(math.Round(curveValue(st.Curve, from, to, progress))))
+		}
+	}
+}
+
+// userGroup owns the running closed-loop users of a concurrency- or max-mode run
+type userGroup struct {
+	r         *Runner
+	ctx       context.Context
+	thinkTime time.Duration
+	rec       *recorder
+	stage     atomic.Pointer[string]
+	cancels   []context.CancelFunc // One per active user, newest last
+	wg        sync.WaitGroup
+}
+
+// startUserGroup starts the result recorder; users are added with scaleTo
+func (r *Runner) startUserGroup(ctx context.Context, thinkTime time.Duration) *userGroup {
+	g := &userGroup{r: r, ctx: ctx, thinkTime: thinkTime, rec: r.startRecorder()}
+	g.setStage("")
+	return g
+}
+
+// setStage labels requests started from now on with the given stage name
+func (g *userGroup) setStage(name string) {
+	g.stage.Store(&name)
+}
+
+// scaleTo starts or stops users until n are active. Stopped users finish
+// their in-flight request before exiting.
+func (g *userGroup) scaleTo(n int) {
+	for len(g.cancels) < n {
+		userCtx, cancel := context.WithCancel(g.ctx)
+		g.cancels = append(g.cancels, cancel)
+		g.wg.Add(1)
+		go g.loop(userCtx)
+	}
+	for len(g.cancels) > n {
+		last := len(g.cancels) - 1
+		g.cancels[last]()
+		g.cancels = g.cancels[:last]
+	}
+	g.r.live.users.Store(int64(len(g.cancels)))
+}
+
+// loop is the request -> response -> think cycle of a single virtual user
+func (g *userGroup) loop(ctx context.Context) {
+	defer g.wg.Done()
+	for ctx.Err() == nil {
+		g.r.sendOne(g.rec.resultsCh, job{stage: *g.stage.Load()})
+		if g.thinkTime > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(g.thinkTime):
+			}
+		}
+	}
+}
+
+// stop cancels every user, waits for in-flight requests and drains the recorder
+func (g *userGroup) stop() {
+	g.scaleTo(0)
+	g.wg.Wait()
+	g.rec.stop()
+}
+
+// runSearchMode looks for the highest RPS that meets the configured SLOs by
+// holding each candidate rate for stepDurationSeconds and evaluating it
+func (r *Runner) runSearchMode() (*BenchmarkResult, error) {
+	sc := r.cfg.Execution.Search
+	log.Printf("Running in search mode (%s): %d..%d RPS, %ds per step (workers=%d, queue=%d, burst=%d).",
+		sc.Strategy, sc.StartRPS, sc.MaxRPS, sc.StepDurationSeconds,
+		r.cfg.Execution.MaxWorkers, r.cfg.Execution.MaxQueueDepth, r.cfg.Execution.RateBurst)
+
+	if sc.StartRPS <= 0 || sc.MaxRPS <= sc.StartRPS {
+		return nil, fmt.Errorf("search mode requires 0 < startRps < maxRps")
+	}
+	if err := r.checkTargets(); err != nil {
+		return nil, err
+	}
+
+	p := r.startPool()
+	pc := r.startPacer(float64(sc.StartRPS))
+	search := &SearchResult{Strategy: sc.Strategy}
+
+	try := func(rps int) bool {
+		step := r.runSearchStep(p, pc, len(search.Steps)+1, rps)
+		search.Steps = append(search.Steps, step)
+		if step.Passed {
+			search.BestRPS = max(search.BestRPS, rps)
+			log.Printf("Search step at %d RPS passed (achieved %.1f, p99 %s, errors %.2f%%).",
+				rps, step.AchievedRPS, step.P99, step.ErrorRate*100)
+		} else {
+			log.Printf("Search step at %d RPS failed: %s.", rps, step.Reason)
+		}
+		return step.Passed
+	}
+
+	switch sc.Strategy {
+	case config.SearchBisect:
+		if try(sc.StartRPS) && !try(sc.MaxRPS) {
+			lo, hi := sc.StartRPS, sc.MaxRPS
+			for hi-lo > sc.PrecisionRPS {
+				mid := lo + (hi-lo)/2
+				if try(mid) {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+		}
+	default: // step; the last step is clamped so maxRps itself is tried
+		for rps := sc.StartRPS; try(rps) && rps < sc.MaxRPS; {
+			rps = min(rps+sc.StepRPS, sc.MaxRPS)
+		}
+	}
+	droppedN := p.stop()
+
+	if search.BestRPS > 0 {
+		log.Printf("Search finished: highest passing rate is %d RPS.", search.BestRPS)
+	} else {
+		log.Printf("Search finished: no rate met the SLOs (lowest tried %d RPS).", sc.StartRPS)
+	}
+	res := r.finish("Search", droppedN)
+	res.Search = search
+	return res, nil
+}
+
+// runSearchStep holds rps for one step, lets in-flight requests land and judges the step against the SLOs
+func (r *Runner) runSearchStep(p *pool, pc pacer, n, rps int) SearchStep {
+	sc := r.cfg.Execution.Search
+	name := fmt.Sprintf("step-%d", n)
+	stepDuration := time.Duration(sc.StepDurationSeconds) * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), stepDuration)
+	defer cancel()
+	pc.setRate(float64(rps))
+	r.beginStage(name)
+	p.schedule(ctx, pc, job{stage: name})
+	r.collector.EndStage(name)
+	// Jobs still queued belong to this step; left in the queue they would
+	// run in the next step and drag its latency down with this one's backlog
+	p.discardQueued()
+	p.waitIdle()
+
+	step := SearchStep{Stage: name, TargetRPS: rps}
+	for _, st := range r.collector.GetResults().Stages {
+		if st.Name == name {
+			step.AchievedRPS = st.AchievedRPS
+			step.Requests = st.Results.TotalRequests
+			step.P99 = st.Results.Percentile(99)
+			if st.Results.TotalRequests > 0 {
+				step.ErrorRate = float64(st.Results.FailedRequests) / float64(st.Results.TotalRequests)
+			}
+		}
+	}
+	step.Reason = checkSLO(sc.SLO, step)
+	step.Passed = step.Reason == ""
+	return step
+}
+
+// checkSLO returns why the step violates the SLOs, or "" if it meets them all
+func checkSLO(slo config.SLOConfig, step SearchStep) string {
+	var reasons []string
+	if step.Requests == 0 {
+		return "no requests completed"
+	}
+	if minRPS := slo.MinAchievedRatio * float64(step.TargetRPS); step.AchievedRPS < minRPS {
+		reasons = append(reasons, fmt.Sprintf("achieved %.1f RPS < %.1f", step.AchievedRPS, minRPS))
+	}
+	if limit := time.Duration(slo.P99LatencyMs) * time.Millisecond; limit > 0 && step.P99 > limit {
+		reasons = append(reasons, fmt.Sprintf("p99 %s > %s", step.P99, limit))
+	}
+	if step.ErrorRate > slo.MaxErrorRate {
+		reasons = append(reasons, fmt.Sprintf("error rate %.2f%% > %.2f%%", step.ErrorRate*100, slo.MaxErrorRate*100))
+	}
+	return strings.Join(reasons, "; ")
+}
 
-	collectorWg := sync.WaitGroup{}
-	collectorWg.Add(1)
+// rampRetuneInterval is how often staged modes recompute their target rate or user count
+const rampRetuneInterval = 100 * time.Millisecond
+
+// minRampRPS keeps the pacer from scheduling arrivals seconds into the future
+// while a stage starts from (or passes through) a near-zero rate.
+const minRampRPS = 1.0
+
+// startRetune runs retunePacer in the background; the returned function
+// waits for it to exit once ctx is done
+func startRetune(ctx context.Context, pc pacer, st config.StageConfig, stageDuration time.Duration) (wait func()) {
+	done := make(chan struct{})
 	go func() {
-		defer collectorWg.Done()
-		for detail := range r.resultsCh {
+		defer close(done)
+		retunePacer(ctx, pc, st, stageDuration)
+	}()
+	return func() { <-done }
+}
+
+// retunePacer updates the pacer to follow the stage curve until ctx is done
+func retunePacer(ctx context.Context, pc pacer, st config.StageConfig, stageDuration time.Duration) {
+	start := time.Now()
+	pc.setRate(stageRate(st, 0))
+	ticker := time.NewTicker(rampRetuneInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			progress := float64(time.Since(start)) / float64(stageDuration)
+			pc.setRate(stageRate(st, progress))
+		}
+	}
+}
+
+// stageRate returns the target RPS at progress (0..1) through a ramp stage
+func stageRate(st config.StageConfig, progress float64) float64 {
+	return math.Max(curveValue(st.Curve, float64(st.StartRPS), float64(st.TargetRPS), progress), minRampRPS)
+}
+
+// curveValue interpolates from -> to at progress (0..1) along the stage curve
+func curveValue(curve string, from, to, progress float64) float64 {
+	progress = math.Max(0, math.Min(1, progress))
+	switch curve {
+	case config.CurveStep:
+		return to
+	case config.CurveExponential:
+		return from * math.Pow(to/from, progress)
+	default: // linear
+		return from + (to-from)*progress
+	}
+}
+
+// recorder forwards worker results to the metrics collector on a single goroutine
+type recorder struct {
+	resultsCh chan metrics.MetricDetail
+	recorded  atomic.Int64 // Results handed to the collector so far
+	wg        sync.WaitGroup
+}
+
+// startRecorder begins draining r.resultsCh into the collector
+func (r *Runner) startRecorder() *recorder {
+	rec := &recorder{resultsCh: r.resultsCh}
+	rec.wg.Add(1)
+	go func() {
+		defer rec.wg.Done()
+		for detail := range rec.resultsCh {
 			r.collector.AppendDetail(detail)
+			rec.recorded.Add(1)
 		}
 	}()
+	return rec
+}
 
-	var workerWg sync.WaitGroup
-	for range maxWorkers {
-		workerWg.Add(1)
+// stop waits for every queued result to reach the collector; no sends may follow
+func (rec *recorder) stop() {
+	close(rec.resultsCh)
+	rec.wg.Wait()
+}
+
+// sendOne selects an endpoint and base URL, performs the request and records
+// the result. A non-zero intended time (open-loop modes) yields a response time
+// that includes any wait in the job queue; closed-loop users pass the zero time.
+func (r *Runner) sendOne(resultsCh chan<- metrics.MetricDetail, j job) {
+	endpointName, endpoint := r.selectEndpoint()
+	baseURL := r.selectBaseURL()
+	r.live.inFlight.Add(1)
+	detail := r.makeRequest(baseURL, endpointName, endpoint)
+	r.live.inFlight.Add(-1)
+	detail.Endpoint = endpointName
+	detail.BaseURL = baseURL
+	detail.Stage = j.stage
+	detail.ResponseTime = detail.Duration
+	if !j.intended.IsZero() {
+		detail.IntendedStart = j.intended
+		detail.ResponseTime = max(detail.Timestamp.Sub(j.intended), detail.Duration)
+	}
+	resultsCh <- detail
+}
+
+// pool is the bounded worker pool fed by the rate scheduler
+type pool struct {
+	jobs      chan job
+	enqueued  atomic.Int64
+	dropped   atomic.Int64
+	workerWg  sync.WaitGroup
+	rec       *recorder
+	collector *metrics.Collector
+	missed    []job // Dropped slots awaiting a corrected latency; only the scheduling goroutine touches it
+}
+
+// startPool launches the result recorder and MaxWorkers workers
+func (r *Runner) startPool() *pool {
+	p := &pool{
+		jobs:      make(chan job, r.cfg.Execution.MaxQueueDepth),
+		rec:       r.startRecorder(),
+		collector: r.collector,
+	}
+	r.live.pool.Store(p)
+
+	for range r.cfg.Execution.MaxWorkers {
+		p.workerWg.Add(1)
 		go func() {
-			defer workerWg.Done()
-			for range jobs {
-				endpointName, endpoint := r.selectEndpoint()
-				baseURL := r.selectBaseURL()
-				detail := r.makeRequest(baseURL, endpointName, endpoint)
-				r.resultsCh <- detail
+			defer p.workerWg.Done()
+			for j := range p.jobs {
+				r.sendOne(p.rec.resultsCh, j)
 			}
 		}()
 	}
+	return p
+}
 
-	limiter := rate.NewLimiter(rate.Limit(r.cfg.Execution.RequestsPerSecond), r.cfg.Execution.RateBurst)
-
-sched:
+// schedule enqueues j at the pacer's rate until ctx is done. When the
+// queue is full the slot is dropped rather than blocking the scheduler, but
+// it still gets a corrected latency: the time from its intended start until
+// the queue has room again (or scheduling ends), a lower bound on what it
+// would have waited. Without it a stall that fills the queue would cap the
+// corrected response times at about one queue's worth of service time.
+func (p *pool) schedule(ctx context.Context, pc pacer, j job) {
+	defer p.recordMissed()
 	for {
-		if err := limiter.Wait(ctx); err != nil {
-			break
+		intended, err := pc.wait(ctx)
+		if err != nil {
+			return
 		}
+		j.intended = intended
 		select {
-		case jobs <- struct{}{}:
+		case p.jobs <- j:
+			p.enqueued.Add(1)
+			p.recordMissed()
 		case <-ctx.Done():
-			break sched
+			return
 		default:
-			dropped.Add(1)
+			p.dropped.Add(1)
+			p.missed = append(p.missed, j)
 		}
 	}
+}
 
-	close(jobs)
-	workerWg.Wait()
-	close(r.resultsCh)
-	collectorWg.Wait()
+// recordMissed records the dropped slots so far with a latency up to now
+func (p *pool) recordMissed() {
+	if len(p.missed) == 0 {
+		return
+	}
+	now := time.Now()
+	for _, j := range p.missed {
+		p.collector.RecordMissed(j.stage, now.Sub(j.intended))
+	}
+	p.missed = p.missed[:0]
+}
 
-	droppedN := dropped.Load()
+// discardQueued drops the jobs no worker has picked up yet, recording them
+// like slots schedule drops. Call it only once scheduling has stopped.
+func (p *pool) discardQueued() {
+	defer p.recordMissed()
+	for {
+		select {
+		case j := <-p.jobs:
+			p.enqueued.Add(-1)
+			p.dropped.Add(1)
+			p.missed = append(p.missed, j)
+		default:
+			return
+		}
+	}
+}
+
+// waitIdle blocks until every enqueued job has been recorded by the
+// collector; the client timeout bounds how long an in-flight request can take
+func (p *pool) waitIdle() {
+	for p.rec.recorded.Load() < p.enqueued.Load() {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// stop drains the pool and returns the number of dropped schedule slots
+func (p *pool) stop() int64 {
+	close(p.jobs)
+	p.workerWg.Wait()
+	p.rec.stop()
+
+	droppedN := p.dropped.Load()
 	if droppedN > 0 {
 		log.Printf("Scheduler dropped %d scheduled requests (queue full; workers could not keep up).", droppedN)
 	}
-	log.Println("Benchmark duration reached.")
+	return droppedN
+}
 
+// finish builds the BenchmarkResult from the collector once a run has drained
+func (r *Runner) finish(modeName string, droppedN int64) *BenchmarkResult {
 	agg := r.collector.GetResults()
-	log.Printf("Fixed RPS mode finished. Total requests attempted: %d", agg.TotalRequests)
+	log.Printf("%s mode finished. Total requests attempted: %d", modeName, agg.TotalRequests)
 
 	return &BenchmarkResult{
 		TotalRequestsMade:        agg.TotalRequests,
@@ -178,7 +686,7 @@ sched:
 		FailedRequests:           agg.FailedRequests,
 		DroppedDueToBackpressure: droppedN,
 		StatusCodes:              agg.StatusCodesCount,
-	}, nil
+	}
 }
 
 // selectEndpoint selects an endpoint based on the configured strategy
@@ -295,12 +803,14 @@ func (r *Runner) makeRequest(baseURL, endpointName string, endpoint config.Endpo
 		}
 	}
 
-	// Create HTTP request
+	// Create HTTP request, traced so the service time can be split into phases
+	tracer := &phaseTracer{}
+	ctx := httptrace.WithClientTrace(context.Background(), tracer.clientTrace())
 	var req *http.Request
 	if body != nil {
-		req, err = http.NewRequest(endpoint.Method, fullURL, bytes.NewReader(body))
+		req, err = http.NewRequestWithContext(ctx, endpoint.Method, fullURL, bytes.NewReader(body))
 	} else {
-		req, err = http.NewRequest(endpoint.Method, fullURL, nil)
+		req, err = http.NewRequestWithContext(ctx, endpoint.Method, fullURL, nil)
 	}
 
 	if err != nil {
@@ -318,26 +828,41 @@ func (r *Runner) makeRequest(baseURL, endpointName string, endpoint config.Endpo
 		req.Header.Set("User-Agent", "benchmarking-tool/2.0")
 	}
 
-	// Execute request
-	resp, err := r.client.Do(req)
-	duration := time.Since(reqStartTime)
+	// Start a new trace unless the endpoint sends its own traceparent
+	var tc traceContext
+	if req.Header.Get("traceparent") == "" {
+		tc = newTraceContext()
+		if !r.cfg.Tracing.DisablePropagation {
+			req.Header.Set("traceparent", tc.traceparent())
+		}
+	}
 
+	// Execute request; the body is drained so Duration includes the download
+	resp, err := r.client.Do(req)
 	if err != nil {
-		return r.createErrorMetric(fullURL, endpoint.Method, err.Error(), reqStartTime)
+		detail := r.createErrorMetric(fullURL, endpoint.Method, err.Error(), reqStartTime)
+		detail.Timings = tracer.timings(time.Time{})
+		detail.BytesSent = int64(len(body))
+		detail.TraceID, detail.SpanID = tc.traceID, tc.spanID
+		return detail
 	}
-	defer func() {
-		_, _ = io.Copy(io.Discard, resp.Body)
-		_ = resp.Body.Close()
-	}()
+	received, _ := io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	bodyDone := time.Now()
 
 	return metrics.MetricDetail{
-		URL:        fullURL,
-		Method:     endpoint.Method,
-		StatusCode: resp.StatusCode,
-		Duration:   duration,
-		IsError:    false,
-		ErrorMsg:   "",
-		Timestamp:  time.Now(),
+		URL:           fullURL,
+		Method:        endpoint.Method,
+		StatusCode:    resp.StatusCode,
+		Duration:      bodyDone.Sub(reqStartTime),
+		IsError:       false,
+		ErrorMsg:      "",
+		Timestamp:     bodyDone,
+		Timings:       tracer.timings(bodyDone),
+		BytesSent:     int64(len(body)),
+		BytesReceived: received,
+		TraceID:       tc.traceID,
+		SpanID:        tc.spanID,
 	}
 }
 
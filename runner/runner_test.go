@@ -1,16 +1,22 @@
 package runner
 
 import (
+	"context"
+	"math"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"benchmarking-tool/config"
 	"benchmarking-tool/metrics"
+
+	"golang.org/x/time/rate"
 )
 
 func TestRunFixedRPS_Integration(t *testing.T) {
@@ -113,6 +119,13 @@ endpointSelection:
 	}
 	col := metrics.NewCollector()
 	r := NewRunner(cfg, col)
+
+	// Sample the live progress midway through the run
+	mid := make(chan Progress, 1)
+	go func() {
+		time.Sleep(500 * time.Millisecond)
+		mid <- r.Progress()
+	}()
 	res, err := r.Run()
 	if err != nil {
 		t.Fatal(err)
@@ -123,4 +136,808 @@ endpointSelection:
 	if res.DroppedDueToBackpressure < 1 {
 		t.Fatalf("expected some dropped requests due to small queue, got %d", res.DroppedDueToBackpressure)
 	}
+
+	p := <-mid
+	if p.Elapsed < 400*time.Millisecond || p.TargetRPS != 80 || p.QueueCap != 2 || p.InFlight < 1 || p.InFlight > 3 || p.Dropped < 1 {
+		t.Errorf("unexpected progress midway: %+v", p)
+	}
+	if final := r.Progress(); final.InFlight != 0 || final.Dropped != res.DroppedDueToBackpressure {
+		t.Errorf("unexpected progress after the run: %+v", final)
+	}
+}
+
+func TestStageRate_Curves(t *testing.T) {
+	testCases := []struct {
+		name     string
+		stage    config.StageConfig
+		progress float64
+		want     float64
+	}{
+		{"linear start", config.StageConfig{StartRPS: 10, TargetRPS: 110, Curve: config.CurveLinear}, 0, 10},
+		{"linear midpoint", config.StageConfig{StartRPS: 10, TargetRPS: 110, Curve: config.CurveLinear}, 0.5, 60},
+		{"linear ramp down", config.StageConfig{StartRPS: 100, TargetRPS: 20, Curve: config.CurveLinear}, 0.75, 40},
+		{"exponential midpoint", config.StageConfig{StartRPS: 10, TargetRPS: 1000, Curve: config.CurveExponential}, 0.5, 100},
+		{"step jumps to target", config.StageConfig{StartRPS: 10, TargetRPS: 200, Curve: config.CurveStep}, 0, 200},
+		{"progress clamped", config.StageConfig{StartRPS: 10, TargetRPS: 20, Curve: config.CurveLinear}, 3, 20},
+		{"floor at minimum rate", config.StageConfig{StartRPS: 0, TargetRPS: 20, Curve: config.CurveLinear}, 0, minRampRPS},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := stageRate(tc.stage, tc.progress)
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("stageRate = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// ratePacer records the rates it is set to
+type ratePacer struct {
+	mu    sync.Mutex
+	rates []float64
+}
+
+func (p *ratePacer) wait(ctx context.Context) (time.Time, error) { return time.Now(), ctx.Err() }
+
+func (p *ratePacer) setRate(rps float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.rates = append(p.rates, rps)
+}
+
+func (p *ratePacer) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.rates)
+}
+
+func TestStartRetune_WaitsForExit(t *testing.T) {
+	pc := &ratePacer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	wait := startRetune(ctx, pc, config.StageConfig{StartRPS: 10, TargetRPS: 100, DurationSeconds: 1}, time.Second)
+	time.Sleep(3 * rampRetuneInterval)
+	cancel()
+	wait()
+
+	n := pc.count()
+	if n < 2 {
+		t.Fatalf("expected the stage to retune the rate, got %d updates", n)
+	}
+	time.Sleep(2 * rampRetuneInterval)
+	if pc.count() != n {
+		t.Fatalf("rate updated after the retune was waited for: %v", pc.rates)
+	}
+}
+
+func TestRunRamp_Integration(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: ramp
+  requestTimeoutMs: 2000
+  stages:
+    - name: warmup
+      startRps: 5
+      targetRps: 20
+      durationSeconds: 1
+    - name: peak
+      targetRps: 40
+      durationSeconds: 1
+      curve: step
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	if _, err := NewRunner(cfg, col).Run(); err != nil {
+		t.Fatal(err)
+	}
+	agg := col.GetResults()
+	if len(agg.Stages) != 2 {
+		t.Fatalf("expected 2 stage results, got %d", len(agg.Stages))
+	}
+	warmup, peak := agg.Stages[0], agg.Stages[1]
+	if warmup.Name != "warmup" || peak.Name != "peak" {
+		t.Fatalf("unexpected stage order: %q, %q", warmup.Name, peak.Name)
+	}
+	if warmup.Results.TotalRequests < 1 || peak.Results.TotalRequests <= warmup.Results.TotalRequests {
+		t.Fatalf("expected peak stage to send more than warmup, got warmup=%d peak=%d",
+			warmup.Results.TotalRequests, peak.Results.TotalRequests)
+	}
+	if warmup.Results.TotalRequests+peak.Results.TotalRequests != agg.TotalRequests {
+		t.Fatalf("stage totals do not add up to %d", agg.TotalRequests)
+	}
+}
+
+func TestRunConcurrency_ClosedLoop(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak, hits := 0, 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		hits++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(50 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: concurrency
+  durationSeconds: 1
+  virtualUsers: 4
+  thinkTimeMs: 50
+  requestTimeoutMs: 2000
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	res, err := NewRunner(cfg, col).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak > 4 {
+		t.Fatalf("peak concurrent requests %d exceeds virtualUsers 4", peak)
+	}
+	// Each user cycles roughly every 100ms (50ms service + 50ms think), so ~40 requests in 1s.
+	if res.TotalRequestsMade < 20 || res.TotalRequestsMade > 48 {
+		t.Fatalf("expected closed-loop request count in [20, 48], got %d", res.TotalRequestsMade)
+	}
+	if int64(hits) != res.TotalRequestsMade {
+		t.Fatalf("server hits %d vs recorded %d", hits, res.TotalRequestsMade)
+	}
+}
+
+func TestNewRunner_IdleConnectionsPerUser(t *testing.T) {
+	cfg := &config.Config{
+		BaseUrls:          []string{"http://a", "http://b"},
+		Endpoints:         map[string]config.EndpointConfig{"root": {Path: "/", Method: "GET"}},
+		EndpointSelection: config.EndpointSelectionConfig{Strategy: "roundRobin"},
+		Execution: config.ExecutionConfig{
+			Mode:             "concurrency",
+			DurationSeconds:  10,
+			RequestTimeoutMs: 1000,
+			Stages:           []config.StageConfig{{StartUsers: 10, TargetUsers: 600, DurationSeconds: 10}},
+		},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	tr := NewRunner(cfg, metrics.NewCollector()).client.Transport.(*http.Transport)
+	if tr.MaxIdleConnsPerHost != 600 || tr.MaxIdleConns != 1200 {
+		t.Fatalf("expected an idle connection per virtual user (600 per host, 1200 in all), got %d / %d (maxWorkers %d)",
+			tr.MaxIdleConnsPerHost, tr.MaxIdleConns, cfg.Execution.MaxWorkers)
+	}
+}
+
+func TestRunConcurrency_StagedUsers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: concurrency
+  requestTimeoutMs: 2000
+  stages:
+    - name: single
+      startUsers: 1
+      targetUsers: 1
+      durationSeconds: 1
+    - name: many
+      targetUsers: 6
+      durationSeconds: 1
+      curve: step
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	if _, err := NewRunner(cfg, col).Run(); err != nil {
+		t.Fatal(err)
+	}
+	agg := col.GetResults()
+	if len(agg.Stages) != 2 {
+		t.Fatalf("expected 2 stage results, got %d", len(agg.Stages))
+	}
+	single, many := agg.Stages[0].Results.TotalRequests, agg.Stages[1].Results.TotalRequests
+	if single < 1 || many < 3*single {
+		t.Fatalf("expected 6 users to send far more than 1, got single=%d many=%d", single, many)
+	}
+}
+
+func TestRunMaxThroughput_SaturatesWorkers(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, peak := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		peak = max(peak, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: max
+  durationSeconds: 1
+  maxWorkers: 5
+  requestTimeoutMs: 2000
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	res, err := NewRunner(cfg, col).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if peak != 5 {
+		t.Fatalf("expected all 5 workers in flight at peak, got %d", peak)
+	}
+	// 5 workers x ~10ms per request gives a ceiling near 500 RPS; well above any limiter default.
+	agg := col.GetResults()
+	if agg.AchievedRPS < 100 {
+		t.Fatalf("expected unthrottled throughput, got %.1f RPS (%d requests)", agg.AchievedRPS, res.TotalRequestsMade)
+	}
+	if res.DroppedDueToBackpressure != 0 {
+		t.Fatalf("max mode should not drop requests, got %d", res.DroppedDueToBackpressure)
+	}
+}
+
+func TestCheckSLO(t *testing.T) {
+	slo := config.SLOConfig{P99LatencyMs: 100, MaxErrorRate: 0.01, MinAchievedRatio: 0.9}
+	testCases := []struct {
+		name string
+		step SearchStep
+		want string
+	}{
+		{"passes", SearchStep{TargetRPS: 100, AchievedRPS: 95, Requests: 950, P99: 80 * time.Millisecond}, ""},
+		{"no requests", SearchStep{TargetRPS: 100}, "no requests"},
+		{"slow", SearchStep{TargetRPS: 100, AchievedRPS: 99, Requests: 990, P99: 150 * time.Millisecond}, "p99"},
+		{"errors", SearchStep{TargetRPS: 100, AchievedRPS: 99, Requests: 990, ErrorRate: 0.05}, "error rate"},
+		{"cannot keep up", SearchStep{TargetRPS: 100, AchievedRPS: 60, Requests: 600}, "achieved"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := checkSLO(slo, tc.step)
+			if tc.want == "" && got != "" {
+				t.Fatalf("expected pass, got %q", got)
+			}
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("expected reason containing %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+// capacityServer answers 200 while the request rate stays within capacity
+// per second and 503 for the excess.
+func capacityServer(capacity int) *httptest.Server {
+	limiter := rate.NewLimiter(rate.Limit(capacity), 5)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.Allow() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestRunSearch_StepFindsCapacity(t *testing.T) {
+	srv := capacityServer(30)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: search
+  requestTimeoutMs: 2000
+  search:
+    strategy: step
+    startRps: 10
+    maxRps: 50
+    stepRps: 15
+    stepDurationSeconds: 1
+    slo:
+      maxErrorRate: 0.05
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	res, err := NewRunner(cfg, col).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Search == nil {
+		t.Fatal("expected search result")
+	}
+	// Steps: 10 (pass), 25 (pass), 40 (fail: ~25% errors).
+	if len(res.Search.Steps) != 3 {
+		t.Fatalf("expected 3 steps, got %+v", res.Search.Steps)
+	}
+	if res.Search.BestRPS != 25 {
+		t.Fatalf("expected best 25 RPS, got %d (steps %+v)", res.Search.BestRPS, res.Search.Steps)
+	}
+	last := res.Search.Steps[2]
+	if last.Passed || !strings.Contains(last.Reason, "error rate") {
+		t.Fatalf("expected last step to fail on error rate, got %+v", last)
+	}
+	if len(col.GetResults().Stages) != 3 {
+		t.Fatalf("expected one stage per step")
+	}
+}
+
+func TestRunSearch_StepReachesMaxRPS(t *testing.T) {
+	srv := capacityServer(1000)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	// 10..50 in steps of 15 does not divide evenly: 10, 25, 40, then 50
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: search
+  requestTimeoutMs: 2000
+  search:
+    strategy: step
+    startRps: 10
+    maxRps: 50
+    stepRps: 15
+    stepDurationSeconds: 1
+    slo:
+      maxErrorRate: 0.05
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Execution.DurationSeconds != 4 {
+		t.Fatalf("expected a worst case of 4 one-second steps, got %ds", cfg.Execution.DurationSeconds)
+	}
+	res, err := NewRunner(cfg, metrics.NewCollector()).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rates []int
+	for _, st := range res.Search.Steps {
+		rates = append(rates, st.TargetRPS)
+	}
+	if len(rates) != 4 || rates[3] != 50 || res.Search.BestRPS != 50 {
+		t.Fatalf("expected steps 10, 25, 40, 50 with best 50, got %v (best %d)", rates, res.Search.BestRPS)
+	}
+}
+
+func TestRunSearchStep_OverloadDoesNotSpillIntoNextStep(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	// One worker serves about 50 RPS, so 300 RPS leaves a full queue behind
+	// that would take a second to work off, far longer than the request timeout
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: search
+  requestTimeoutMs: 200
+  maxWorkers: 1
+  maxQueueDepth: 50
+  search:
+    strategy: bisect
+    startRps: 10
+    maxRps: 300
+    stepDurationSeconds: 1
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRunner(cfg, metrics.NewCollector())
+	p := r.startPool()
+	pc := r.startPacer(300)
+	defer p.stop()
+
+	overloaded := r.runSearchStep(p, pc, 1, 300)
+	if overloaded.Passed {
+		t.Fatalf("expected the overloaded step to fail, got %+v", overloaded)
+	}
+	before := hits.Load()
+	next := r.runSearchStep(p, pc, 2, 10)
+	if !next.Passed {
+		t.Fatalf("expected the step after the overload to pass, got %+v", next)
+	}
+	if sent := hits.Load() - before; sent != next.Requests {
+		t.Fatalf("step 2 recorded %d requests but the server saw %d; the rest were left over from step 1", next.Requests, sent)
+	}
+}
+
+func TestRunSearch_Bisect(t *testing.T) {
+	srv := capacityServer(30)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: search
+  requestTimeoutMs: 2000
+  search:
+    strategy: bisect
+    startRps: 10
+    maxRps: 60
+    precisionRps: 15
+    stepDurationSeconds: 1
+    slo:
+      maxErrorRate: 0.05
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := NewRunner(cfg, metrics.NewCollector()).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Probes 10 (pass), 60 (fail), 35 (fail), 22 (pass); bracket [22, 35] is within precision.
+	targets := make([]int, 0, len(res.Search.Steps))
+	for _, st := range res.Search.Steps {
+		targets = append(targets, st.TargetRPS)
+	}
+	if len(targets) != 4 || targets[0] != 10 || targets[1] != 60 || targets[2] != 35 || targets[3] != 22 {
+		t.Fatalf("unexpected bisect probes %v", targets)
+	}
+	if res.Search.BestRPS != 22 {
+		t.Fatalf("expected best 22 RPS, got %d", res.Search.BestRPS)
+	}
+}
+
+func TestRunFixedRPS_CorrectedResponseTimeIncludesQueueing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	// One worker serving 100ms requests at 20 RPS: jobs pile up in the queue.
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: fixed
+  durationSeconds: 1
+  requestsPerSecond: 20
+  requestTimeoutMs: 2000
+  maxWorkers: 1
+  maxQueueDepth: 50
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	if _, err := NewRunner(cfg, col).Run(); err != nil {
+		t.Fatal(err)
+	}
+	agg := col.GetResults()
+	if agg.MaxDuration > 500*time.Millisecond {
+		t.Fatalf("service time should stay near 100ms, got max %v", agg.MaxDuration)
+	}
+	if agg.MaxResponseTime < time.Second {
+		t.Fatalf("corrected response time should include queueing (>1s), got max %v", agg.MaxResponseTime)
+	}
+	if agg.AvgResponseTime <= agg.AvgDuration {
+		t.Fatalf("expected avg response time %v > avg service time %v", agg.AvgResponseTime, agg.AvgDuration)
+	}
+}
+
+func TestRunFixedRPS_StallCountsDroppedSlots(t *testing.T) {
+	var stalled atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if stalled.CompareAndSwap(false, true) {
+			time.Sleep(time.Second) // The first request stalls; the rest are instant
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	// While the single worker is stuck, the one-slot queue fills and later slots are dropped.
+	yaml := `baseUrls:
+  - "` + srv.URL + `"
+execution:
+  mode: fixed
+  durationSeconds: 2
+  requestsPerSecond: 100
+  requestTimeoutMs: 5000
+  maxWorkers: 1
+  maxQueueDepth: 1
+endpoints:
+  root:
+    path: "/"
+    method: GET
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	res, err := NewRunner(cfg, col).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.DroppedDueToBackpressure < 50 {
+		t.Fatalf("expected the stall to drop most slots for a second, got %d dropped", res.DroppedDueToBackpressure)
+	}
+	agg := col.GetResults()
+	if p50 := agg.Percentile(50); p50 > 100*time.Millisecond {
+		t.Fatalf("service time should be fast apart from the stall, got p50 %v", p50)
+	}
+	// About half the slots fell in the stall, waiting up to a second each
+	if p75 := agg.ResponsePercentile(75); p75 < 200*time.Millisecond {
+		t.Fatalf("dropped slots should show the stall in corrected latency, got p75 %v", p75)
+	}
+	if agg.TotalRequests >= agg.ResponseLatency.Count() {
+		t.Fatalf("expected dropped slots in the response-time distribution, got %d samples for %d requests",
+			agg.ResponseLatency.Count(), agg.TotalRequests)
+	}
+}
+
+func TestRun_RecordsEndpointAndBaseURL(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	srvA := httptest.NewServer(handler)
+	defer srvA.Close()
+	srvB := httptest.NewServer(handler)
+	defer srvB.Close()
+
+	dir := t.TempDir()
+	cfgPath := filepath.Join(dir, "bench.yaml")
+	yaml := `baseUrls:
+  - "` + srvA.URL + `"
+  - "` + srvB.URL + `"
+execution:
+  mode: concurrency
+  durationSeconds: 1
+  virtualUsers: 2
+  thinkTimeMs: 20
+  requestTimeoutMs: 2000
+endpoints:
+  ok:
+    path: "/ok"
+    method: GET
+  fail:
+    path: "/fail"
+    method: GET
+endpointSelection:
+  strategy: roundRobin
+`
+	if err := os.WriteFile(cfgPath, []byte(yaml), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.LoadConfig(cfgPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	col := metrics.NewCollector()
+	if _, err := NewRunner(cfg, col).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	agg := col.GetResults()
+	if len(agg.Endpoints) != 2 || len(agg.BaseURLs) != 2 {
+		t.Fatalf("expected 2 endpoints and 2 base URLs, got %v and %v", len(agg.Endpoints), len(agg.BaseURLs))
+	}
+	ok, fail := agg.Endpoints["ok"], agg.Endpoints["fail"]
+	if ok.TotalRequests == 0 || ok.FailedRequests != 0 {
+		t.Fatalf("ok endpoint: %d requests, %d failed", ok.TotalRequests, ok.FailedRequests)
+	}
+	if fail.TotalRequests == 0 || fail.FailedRequests != fail.TotalRequests {
+		t.Fatalf("fail endpoint: %d requests, %d failed", fail.TotalRequests, fail.FailedRequests)
+	}
+	a, b := agg.BaseURLs[srvA.URL], agg.BaseURLs[srvB.URL]
+	if a.TotalRequests == 0 || b.TotalRequests == 0 || a.TotalRequests+b.TotalRequests != agg.TotalRequests {
+		t.Fatalf("base URL split %d + %d does not cover %d requests", a.TotalRequests, b.TotalRequests, agg.TotalRequests)
+	}
+}
+
+func TestMakeRequest_PhaseTimings(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(30 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Repeat("x", 64<<10)))
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Execution: config.ExecutionConfig{RequestTimeoutMs: 2000}}
+	r := NewRunner(cfg, metrics.NewCollector())
+	r.client = srv.Client()
+	endpoint := config.EndpointConfig{Path: "/", Method: http.MethodGet}
+
+	first := r.makeRequest(srv.URL, "root", endpoint)
+	if first.IsError {
+		t.Fatalf("request failed: %s", first.ErrorMsg)
+	}
+	tm := first.Timings
+	if tm.ConnReused || tm.TCPConnect <= 0 || tm.TLSHandshake <= 0 {
+		t.Fatalf("expected a new TLS connection, got %+v", tm)
+	}
+	if tm.TimeToFirstByte < 30*time.Millisecond {
+		t.Fatalf("time to first byte %v should include the 30ms of server processing", tm.TimeToFirstByte)
+	}
+	if sum := tm.TCPConnect + tm.TLSHandshake + tm.TimeToFirstByte + tm.Download; sum > first.Duration {
+		t.Fatalf("phases sum to %v, more than the service time %v", sum, first.Duration)
+	}
+
+	if first.BytesReceived != 64<<10 || first.BytesSent != 0 {
+		t.Fatalf("expected 0 B sent and %d B received, got %d and %d", 64<<10, first.BytesSent, first.BytesReceived)
+	}
+
+	second := r.makeRequest(srv.URL, "root", endpoint)
+	if !second.Timings.ConnReused || second.Timings.TCPConnect != 0 || second.Timings.TLSHandshake != 0 {
+		t.Fatalf("expected the second request to reuse the connection, got %+v", second.Timings)
+	}
+}
+
+func TestMakeRequest_TraceContext(t *testing.T) {
+	var mu sync.Mutex
+	var headers []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers = append(headers, r.Header.Get("traceparent"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := &config.Config{Execution: config.ExecutionConfig{RequestTimeoutMs: 2000}}
+	r := NewRunner(cfg, metrics.NewCollector())
+	endpoint := config.EndpointConfig{Path: "/", Method: http.MethodGet}
+
+	first := r.makeRequest(srv.URL, "root", endpoint)
+	second := r.makeRequest(srv.URL, "root", endpoint)
+	if len(first.TraceID) != 32 || len(first.SpanID) != 16 || first.TraceID == second.TraceID {
+		t.Fatalf("expected a new trace per request, got %q and %q", first.TraceID, second.TraceID)
+	}
+	if want := "00-" + first.TraceID + "-" + first.SpanID + "-01"; headers[0] != want {
+		t.Fatalf("traceparent = %q, want %q", headers[0], want)
+	}
+
+	cfg.Tracing.DisablePropagation = true
+	third := r.makeRequest(srv.URL, "root", endpoint)
+	if headers[2] != "" || third.TraceID == "" {
+		t.Fatalf("with propagation disabled the header should be omitted but the span still traced, got %q / %q", headers[2], third.TraceID)
+	}
+
+	endpoint.Headers = map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}
+	cfg.Tracing.DisablePropagation = false
+	own := r.makeRequest(srv.URL, "root", endpoint)
+	if headers[3] != endpoint.Headers["traceparent"] || own.TraceID != "" {
+		t.Fatalf("an endpoint's own traceparent should be sent unchanged, got %q / %q", headers[3], own.TraceID)
+	}
 }
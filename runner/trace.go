@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"benchmarking-tool/metrics"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// phaseTracer records the httptrace events of one request. Callbacks may
+// run on transport goroutines (parallel dials), hence the mutex.
+type phaseTracer struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+// clientTrace returns the hooks that feed the tracer
+func (t *phaseTracer) clientTrace() *httptrace.ClientTrace {
+	mark := func(at *time.Time) {
+		t.mu.Lock()
+		*at = time.Now()
+		t.mu.Unlock()
+	}
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { mark(&t.dnsDone) },
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			if t.connectStart.IsZero() { // keep the first of parallel dials
+				t.connectStart = time.Now()
+			}
+			t.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				mark(&t.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { mark(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { mark(&t.tlsDone) },
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { mark(&t.wroteRequest) },
+		GotFirstResponseByte: func() { mark(&t.firstByte) },
+	}
+}
+
+// timings converts the recorded events into phase durations; bodyDone is
+// when the response body was fully read (zero if it never was).
+func (t *phaseTracer) timings(bodyDone time.Time) metrics.RequestTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return metrics.RequestTimings{
+		DNSLookup:       span(t.dnsStart, t.dnsDone),
+		TCPConnect:      span(t.connectStart, t.connectDone),
+		TLSHandshake:    span(t.tlsStart, t.tlsDone),
+		TimeToFirstByte: span(t.wroteRequest, t.firstByte),
+		Download:        span(t.firstByte, bodyDone),
+		ConnReused:      t.reused,
+	}
+}
+
+// span returns end-start when both events happened in order, else 0
+func span(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// traceContext is the W3C trace context of one request: a new trace per
+// request, with the client span as its root
+type traceContext struct {
+	traceID string // 32 hex digits
+	spanID  string // 16 hex digits
+}
+
+func newTraceContext() traceContext {
+	var id [24]byte
+	_, _ = rand.Read(id[:]) // Never fails on supported platforms
+	return traceContext{traceID: hex.EncodeToString(id[:16]), spanID: hex.EncodeToString(id[16:])}
+}
+
+// traceparent formats the header value, flagged as sampled so the server records its spans
+func (tc traceContext) traceparent() string {
+	return "00-" + tc.traceID + "-" + tc.spanID + "-01"
+}
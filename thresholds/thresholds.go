@@ -0,0 +1,143 @@
+package thresholds
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+)
+
+// Check operators
+const (
+	AtMost  = "<="
+	AtLeast = ">="
+)
+
+// Result is the outcome of one threshold check
+type Result struct {
+	Scope  string  `json:"scope,omitempty"` // Endpoint name; empty for the whole run
+	Metric string  `json:"metric"`          // "error_rate", "achieved_rps", "dropped_requests" or a percentile label such as "p95"
+	Op     string  `json:"op"`              // AtMost or AtLeast
+	Limit  float64 `json:"limit"`
+	Actual float64 `json:"actual"`
+	Unit   string  `json:"unit,omitempty"` // "%", "ms", "req/s" or none for counts
+	Passed bool    `json:"passed"`
+	Reason string  `json:"reason,omitempty"` // Why the check failed
+}
+
+// Name describes the check, e.g. "get_user p95 <= 200ms"
+func (r Result) Name() string {
+	name := fmt.Sprintf("%s %s %s", r.Metric, r.Op, withUnit(formatValue(r.Limit), r.Unit))
+	if r.Scope != "" {
+		name = r.Scope + " " + name
+	}
+	return name
+}
+
+// Measured renders the actual value with its unit
+func (r Result) Measured() string {
+	if r.Unit == "" {
+		return formatValue(r.Actual)
+	}
+	return withUnit(strconv.FormatFloat(r.Actual, 'f', 2, 64), r.Unit)
+}
+
+// Failed counts the checks that did not pass
+func Failed(results []Result) int {
+	n := 0
+	for _, r := range results {
+		if !r.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// Evaluate checks a run against every configured threshold: the global
+// limits first, then each endpoint by name. dropped is the number of
+// requests the runner dropped under backpressure. A scope with thresholds
+// but no recorded requests fails its checks.
+func Evaluate(th config.ThresholdsConfig, results metrics.AggregatedResults, dropped int64) []Result {
+	out := evaluateSet("", th.ThresholdSet, results)
+	if th.MaxDropped != nil {
+		out = append(out, newResult("", "dropped_requests", AtMost, "", float64(*th.MaxDropped), float64(dropped)))
+	}
+
+	names := make([]string, 0, len(th.Endpoints))
+	for name := range th.Endpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, evaluateSet(name, th.Endpoints[name], results.Endpoints[name])...)
+	}
+	return out
+}
+
+// evaluateSet checks one scope's limits
+func evaluateSet(scope string, set config.ThresholdSet, res metrics.AggregatedResults) []Result {
+	var out []Result
+	check := func(metric, op, unit string, limit, actual float64) {
+		r := newResult(scope, metric, op, unit, limit, actual)
+		if res.TotalRequests == 0 {
+			r.Passed, r.Reason = false, "no requests recorded"
+		}
+		out = append(out, r)
+	}
+
+	if set.MaxErrorRate != nil {
+		rate := 0.0
+		if res.TotalRequests > 0 {
+			rate = float64(res.FailedRequests) / float64(res.TotalRequests)
+		}
+		check("error_rate", AtMost, "%", *set.MaxErrorRate*100, rate*100)
+	}
+	for _, limit := range set.PercentileLimits() {
+		check(metrics.PercentileLabel(limit.Percentile), AtMost, "ms", limit.MaxMs, durationMs(res.Percentile(limit.Percentile)))
+	}
+	if set.MinRPS != nil {
+		check("achieved_rps", AtLeast, "req/s", *set.MinRPS, res.AchievedRPS)
+	}
+	return out
+}
+
+// newResult compares actual against limit
+func newResult(scope, metric, op, unit string, limit, actual float64) Result {
+	r := Result{Scope: scope, Metric: metric, Op: op, Limit: limit, Actual: actual, Unit: unit}
+	limitText := withUnit(formatValue(limit), unit)
+	switch {
+	case op == AtMost && actual > limit:
+		r.Reason = fmt.Sprintf("measured %s exceeds %s", r.Measured(), limitText)
+	case op == AtLeast && actual < limit:
+		r.Reason = fmt.Sprintf("measured %s is below %s", r.Measured(), limitText)
+	default:
+		r.Passed = true
+	}
+	return r
+}
+
+// withUnit appends a unit; symbols attach, words are spaced
+func withUnit(value, unit string) string {
+	switch unit {
+	case "":
+		return value
+	case "%", "ms":
+		return value + unit
+	default:
+		return value + " " + unit
+	}
+}
+
+// formatValue renders a limit without trailing zeros; rounding hides the
+// float noise of converting fractions to percentages.
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
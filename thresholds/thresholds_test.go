@@ -0,0 +1,113 @@
+package thresholds
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"benchmarking-tool/config"
+	"benchmarking-tool/metrics"
+)
+
+// testResults records 100 requests to "fast" (1..100ms, all successful) and
+// 10 to "slow" (1s each, half failing).
+func testResults() metrics.AggregatedResults {
+	collector := metrics.NewCollector()
+	now := time.Now()
+	for i := 1; i <= 100; i++ {
+		collector.AppendDetail(metrics.MetricDetail{
+			Endpoint: "fast", StatusCode: 200, Duration: time.Duration(i) * time.Millisecond, Timestamp: now,
+		})
+	}
+	for i := range 10 {
+		status := 200
+		if i%2 == 0 {
+			status = 500
+		}
+		collector.AppendDetail(metrics.MetricDetail{
+			Endpoint: "slow", StatusCode: status, Duration: time.Second, Timestamp: now,
+		})
+	}
+	return collector.GetResults()
+}
+
+func rate(v float64) *float64 { return &v }
+
+func TestEvaluate(t *testing.T) {
+	th := config.ThresholdsConfig{
+		ThresholdSet: config.ThresholdSet{MaxErrorRate: rate(0.1)},
+		Endpoints: map[string]config.ThresholdSet{
+			"slow": {MaxErrorRate: rate(0.01)},
+			"fast": {Percentiles: map[string]float64{"p99": 50, "p50": 60}},
+		},
+	}
+	results := Evaluate(th, testResults(), 0)
+
+	want := []struct {
+		name   string
+		passed bool
+	}{
+		{"error_rate <= 10%", true}, // 5 of 110 failed
+		{"fast p50 <= 60ms", true},
+		{"fast p99 <= 50ms", false},
+		{"slow error_rate <= 1%", false},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d results, got %+v", len(want), results)
+	}
+	for i, w := range want {
+		if results[i].Name() != w.name || results[i].Passed != w.passed {
+			t.Errorf("result %d: got %q passed=%v, want %q passed=%v", i, results[i].Name(), results[i].Passed, w.name, w.passed)
+		}
+	}
+	if got := results[2].Actual; math.Abs(got-99) > 0.1 {
+		t.Errorf("expected fast p99 measured as about 99ms, got %v", got)
+	}
+	if results[3].Reason != "measured 50.00% exceeds 1%" {
+		t.Errorf("unexpected failure reason %q", results[3].Reason)
+	}
+	if Failed(results) != 2 {
+		t.Errorf("expected 2 failed checks, got %d", Failed(results))
+	}
+}
+
+func TestEvaluate_ThroughputAndDropped(t *testing.T) {
+	maxDropped := int64(2)
+	th := config.ThresholdsConfig{
+		MaxDropped: &maxDropped,
+		Endpoints: map[string]config.ThresholdSet{
+			"slow": {MinRPS: rate(20)}, // 10 requests over 1s
+			"fast": {MinRPS: rate(20)},
+		},
+	}
+	results := Evaluate(th, testResults(), 3)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 checks, got %+v", results)
+	}
+	dropped, fast, slow := results[0], results[1], results[2]
+	if dropped.Name() != "dropped_requests <= 2" || dropped.Passed || dropped.Reason != "measured 3 exceeds 2" {
+		t.Errorf("unexpected dropped check %+v", dropped)
+	}
+	if fast.Name() != "fast achieved_rps >= 20 req/s" || !fast.Passed {
+		t.Errorf("expected fast to pass its rate limit, got %+v", fast)
+	}
+	if slow.Passed || slow.Reason != "measured 10.00 req/s is below 20 req/s" {
+		t.Errorf("expected slow to fail its rate limit, got %+v", slow)
+	}
+}
+
+func TestEvaluate_EndpointWithoutRequests(t *testing.T) {
+	th := config.ThresholdsConfig{Endpoints: map[string]config.ThresholdSet{
+		"unused": {MaxErrorRate: rate(0.5)},
+	}}
+	results := Evaluate(th, testResults(), 0)
+	if len(results) != 1 || results[0].Passed || results[0].Reason != "no requests recorded" {
+		t.Fatalf("expected a failed check for an endpoint without requests, got %+v", results)
+	}
+}
+
+func TestEvaluate_NoThresholds(t *testing.T) {
+	if results := Evaluate(config.ThresholdsConfig{}, testResults(), 5); len(results) != 0 {
+		t.Fatalf("expected no checks, got %+v", results)
+	}
+}